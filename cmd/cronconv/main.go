@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// cronconv 在各类 cron 方言以及 systemd 的 OnCalendar 表达式之间进行转换
+//
+// 用法：
+//
+//	cronconv -from quartz -to systemd '0 0/15 9-17 ? * MON-FRI'
+//
+// 未指定表达式时，从标准输入中按行读取，空行以及以 # 开头的行会被忽略。
+// 可用的格式有 scheduled、vixie、quartz 和 systemd，具体可参考 [dialect] 包。
+//
+// [dialect]: https://pkg.go.dev/github.com/issue9/scheduled/schedulers/dialect
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/issue9/scheduled/schedulers/dialect"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// 执行转换并返回退出码
+//
+// 全部转换成功返回 0，有无法转换的表达式返回 1，参数错误返回 2。
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cronconv", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fromName := fs.String("from", "vixie", "源表达式的格式：scheduled、vixie、quartz 或 systemd")
+	toName := fs.String("to", "scheduled", "目标表达式的格式：scheduled、vixie、quartz 或 systemd")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, err := dialect.ParseDialect(*fromName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	to, err := dialect.ParseDialect(*toName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	specs := fs.Args()
+	if len(specs) == 0 {
+		s := bufio.NewScanner(stdin)
+		for s.Scan() {
+			if line := strings.TrimSpace(s.Text()); line != "" && line[0] != '#' {
+				specs = append(specs, line)
+			}
+		}
+		if err := s.Err(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	code := 0
+	for _, spec := range specs {
+		result, err := dialect.Convert(spec, from, to)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 1
+			continue
+		}
+		fmt.Fprintln(stdout, result)
+	}
+	return code
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestRun(t *testing.T) {
+	a := assert.New(t, false)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := run([]string{"-from", "quartz", "-to", "systemd", "0 0/15 9-17 ? * MON-FRI"}, nil, stdout, stderr)
+	a.Equal(code, 0).
+		Equal(stdout.String(), "Mon..Fri *-*-* 09..17:00/15:00\n").
+		Empty(stderr.String())
+
+	// 从标准输入读取，部分无法转换。
+	stdout.Reset()
+	stderr.Reset()
+	stdin := strings.NewReader("# comment\n*/5 * * * *\n\n0 9 1,15 * 1\n")
+	code = run([]string{"-to", "systemd"}, stdin, stdout, stderr)
+	a.Equal(code, 1).
+		Equal(stdout.String(), "*-*-* *:00/5:00\n").
+		Equal(stderr.String(), "can not convert day-of-month or day-of-week 1,15 1 to systemd\n")
+
+	// 语法错误
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-from", "quartz", "0 0 12 * * *"}, nil, stdout, stderr)
+	a.Equal(code, 1).Empty(stdout.String()).NotEmpty(stderr.String())
+
+	// 参数错误
+	stderr.Reset()
+	a.Equal(run([]string{"-from", "fcron", "* * * * *"}, nil, stdout, stderr), 2).
+		Equal(stderr.String(), "invalid dialect fcron\n")
+
+	stderr.Reset()
+	a.Equal(run([]string{"-to", "fcron", "* * * * *"}, nil, stdout, stderr), 2)
+
+	stderr.Reset()
+	a.Equal(run([]string{"-x"}, nil, stdout, stderr), 2).NotEmpty(stderr.String())
+}
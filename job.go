@@ -12,6 +12,7 @@ import (
 
 	"github.com/issue9/localeutil"
 
+	"github.com/issue9/scheduled/schedulers"
 	"github.com/issue9/scheduled/schedulers/at"
 	"github.com/issue9/scheduled/schedulers/cron"
 	"github.com/issue9/scheduled/schedulers/ticker"
@@ -20,16 +21,31 @@ import (
 // JobFunc 每一个定时任务实际上执行的函数签名
 type JobFunc = func(time.Time) error
 
+// ContextJobFunc 可感知取消操作的任务函数签名
+//
+// 在以下情况下 ctx 会被取消：
+//   - 传递给 [Server.Serve] 的 ctx 被取消；
+//   - 通过添加任务时返回的 [context.CancelFunc] 取消了任务；
+//   - 任务的执行时间超过了指定的超时时间；
+//
+// 任务函数应该在 ctx 被取消之后尽快返回。
+type ContextJobFunc = func(ctx context.Context, at time.Time) error
+
 // Job 定时任务
 type Job struct {
-	s     Scheduler
-	title localeutil.Stringer
-	f     JobFunc
-	delay bool
+	s       Scheduler
+	title   localeutil.Stringer
+	f       ContextJobFunc
+	delay   bool
+	timeout time.Duration
+
+	ctx    context.Context // 任务的生命周期，取消任务时被取消。
+	cancel context.CancelFunc
 
 	// 以下内容需要上锁
 
 	locker sync.RWMutex
+	gen    int // 初始化时 Server.Serve 的运行次数
 	state  State
 	err    error     // 出错时的错误内容
 	prev   time.Time // 上次实际上执行的时间
@@ -78,60 +94,151 @@ func (j *Job) Err() (err error) {
 // 即从任务执行完成的时间点计算下一次执行时间。
 func (j *Job) Delay() bool { return j.delay }
 
+// Timeout 每次执行的超时时间
+//
+// 0 表示不限制。
+func (j *Job) Timeout() time.Duration { return j.timeout }
+
+func newJob(title localeutil.Stringer, f ContextJobFunc, scheduler Scheduler, delay bool, timeout time.Duration) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Job{
+		s:       scheduler,
+		title:   title,
+		f:       f,
+		delay:   delay,
+		timeout: timeout,
+
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// 将 [JobFunc] 转换为 [ContextJobFunc]
+func wrap(f JobFunc) ContextJobFunc {
+	return func(_ context.Context, at time.Time) error { return f(at) }
+}
+
+// 以下几个修改状态的方法，都不会在持有锁的情况下调用用户的代码，
+// 包括 j.f 和 j.s.Next，保证 [Job.State] 等方法在任何时候都不会被阻塞。
+
 func (j *Job) calcState(now time.Time) {
+	next := j.s.Next(now) // 先计算 next，保证调用者重复调用 run 时能获取正确的 next。
+
 	j.locker.Lock()
 	defer j.locker.Unlock()
 
 	j.state = Running
 	j.prev = j.next
-	j.next = j.s.Next(now) // 先计算 next，保证调用者重复调用 run 时能获取正确的 next。
+	j.next = next
 }
 
 // 运行当前的任务
-func (j *Job) run(at time.Time, errlog, infolog Logger) {
+//
+// ctx 为 [Server.Serve] 的参数，ctx 被取消时，也会取消正在执行的任务。
+func (j *Job) run(ctx context.Context, at time.Time, errlog, infolog Logger) {
 	go infolog.LocaleString(localeutil.Phrase("scheduled: start job %s at %s", j.Title(), at.String()))
 
+	err := j.call(ctx, at)
+	next := j.s.Next(time.Now()) // j.f 可能会花费大量时间，所以重新计算 next
+
 	j.locker.Lock()
-	defer j.locker.Unlock()
+	j.err = err
+	if err != nil {
+		j.state = Failed
+	} else {
+		j.state = Stopped
+	}
+	j.next = next
+	j.locker.Unlock()
+
+	if err != nil {
+		errlog.Error(err)
+	}
+}
+
+// 执行 j.f 并将 panic 转换为错误返回
+func (j *Job) call(ctx context.Context, at time.Time) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(j.ctx, cancel) // 任务被取消
+	defer stop()
+
+	if j.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
 
 	defer func() {
 		if msg := recover(); msg != nil {
-			if err, ok := msg.(error); ok {
-				j.err = err
+			if e, ok := msg.(error); ok {
+				err = e
 			} else {
-				j.err = localeutil.Error("recover msg %v", msg)
+				err = localeutil.Error("recover msg %v", msg)
 			}
-			j.state = Failed
-			errlog.Error(j.err) // 不像 infolog 一样采用异步，可能会在执行过程中 j.err 被改值。
 		}
 	}()
 
-	if j.err = j.f(at); j.err != nil {
-		j.state = Failed
-		errlog.Error(j.err)
-	} else {
-		j.state = Stopped
+	return j.f(ctx, at)
+}
+
+// 初始化当前任务，获取其下次执行时间。
+//
+// gen 为 [Server.Serve] 的运行次数，每次运行只会初始化一次，
+// 避免 Serve 和 Add 同时初始化，改变 ticker 等有状态调度器的结果。
+func (j *Job) init(now time.Time, gen int) {
+	j.locker.Lock()
+	if j.gen == gen {
+		j.locker.Unlock()
+		return
 	}
+	j.gen = gen
+	j.next = time.Time{} // 初始化完成之前不参与调度
+	j.locker.Unlock()
+
+	next := j.s.Next(now)
 
-	j.next = j.s.Next(time.Now()) // j.f 可能会花费大量时间，所以重新计算 next
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.next = next
 }
 
-// 初始化当前任务，获取其下次执行时间。
-func (j *Job) init(now time.Time) {
+// 调度规则发生了变化，重新计算下一次的执行时间。
+//
+// 正在执行的 delay 任务会在执行完成之后重新计算，此处不作处理。
+func (j *Job) reset(now time.Time) {
+	next := j.s.Next(now)
+
 	j.locker.Lock()
 	defer j.locker.Unlock()
-	j.next = j.s.Next(now)
+	if !j.delay || j.state != Running {
+		j.next = next
+	}
+}
+
+// 是否处于等待执行状态
+//
+// 未执行完的 delay 任务需要在执行完成之后才能计算下一次的执行时间，
+// 在此之前不参与调度。已经取消的任务也不再参与调度。
+func (j *Job) waiting() bool {
+	if j.ctx.Err() != nil {
+		return false
+	}
+
+	j.locker.RLock()
+	defer j.locker.RUnlock()
+	return !j.next.IsZero() && (!j.delay || j.state != Running)
 }
 
 func sortJobs(jobs []*Job) {
 	slices.SortFunc(jobs, func(i, j *Job) int {
-		if j.next.IsZero() {
+		in, jn := i.Next(), j.Next()
+		if jn.IsZero() {
 			return -1
 		}
-		if i.next.IsZero() {
+		if in.IsZero() {
 			return 1
 		}
-		return i.next.Compare(j.next)
+		return in.Compare(jn)
 	})
 }
 
@@ -142,8 +249,8 @@ func (s *Server) Jobs() []*Job {
 	// NOTE: jobs 有顺序要求，如果直接返回给用户，
 	// 用户可能会对数据进行排序，造成无法使用，所以返回副本。
 
-	jobs := slices.Clone(s.jobs)
-	slices.SortFunc(jobs, func(i, j *Job) int { return i.next.Compare(j.next) })
+	jobs := s.snapshot()
+	slices.SortFunc(jobs, func(i, j *Job) int { return i.Next().Compare(j.Next()) })
 	return jobs
 }
 
@@ -154,15 +261,56 @@ func (s *Server) Tick(title localeutil.Stringer, f JobFunc, dur time.Duration, i
 	return s.New(title, f, ticker.Tick(dur, imm), delay)
 }
 
+// AddTick 添加一个新的定时任务
+//
+// 与 [Server.Tick] 相同，但是在参数错误时返回 [InvalidError] 而不是 panic。
+func (s *Server) AddTick(title localeutil.Stringer, f JobFunc, dur time.Duration, imm, delay bool) (context.CancelFunc, error) {
+	scheduler, err := ticker.New(dur, imm)
+	if err != nil {
+		return nil, err
+	}
+	return s.Add(title, f, scheduler, delay)
+}
+
+// TickAligned 添加一个与时钟对齐的固定时间段的定时任务
+//
+// 执行时间总是 [Server.Location] 中时钟时间为 dur 的整数倍，
+// 具体可以参考 [ticker.Aligned]。
+func (s *Server) TickAligned(title localeutil.Stringer, f JobFunc, dur time.Duration, delay bool) context.CancelFunc {
+	return s.New(title, f, ticker.Aligned(dur, s.Location()), delay)
+}
+
+// AddTickAligned 添加一个与时钟对齐的固定时间段的定时任务
+//
+// 与 [Server.TickAligned] 相同，但是在参数错误时返回 [InvalidError] 而不是 panic。
+func (s *Server) AddTickAligned(title localeutil.Stringer, f JobFunc, dur time.Duration, delay bool) (context.CancelFunc, error) {
+	scheduler, err := ticker.NewAligned(dur, s.Location())
+	if err != nil {
+		return nil, err
+	}
+	return s.Add(title, f, scheduler, delay)
+}
+
 // Cron 使用 cron 表达式新建一个定时任务
 //
-// 具体文件可以参考 [cron.Parse]
+// 具体文件可以参考 [cron.Parse]，表达式错误时 panic。
 func (s *Server) Cron(title localeutil.Stringer, f JobFunc, spec string, delay bool) context.CancelFunc {
-	scheduler, err := cron.Parse(spec, s.Location())
+	cancel, err := s.AddCron(title, f, spec, delay)
 	if err != nil {
 		panic(err)
 	}
-	return s.New(title, f, scheduler, delay)
+	return cancel
+}
+
+// AddCron 使用 cron 表达式新建一个定时任务
+//
+// 与 [Server.Cron] 相同，但是在表达式错误时返回 [InvalidError] 而不是 panic。
+func (s *Server) AddCron(title localeutil.Stringer, f JobFunc, spec string, delay bool) (context.CancelFunc, error) {
+	scheduler, err := cron.Parse(spec, s.Location())
+	if err != nil {
+		return nil, &InvalidError{Spec: spec, Err: err}
+	}
+	return s.Add(title, f, scheduler, delay)
 }
 
 // At 添加 At 类型的定时器
@@ -172,26 +320,101 @@ func (s *Server) At(title localeutil.Stringer, f JobFunc, t time.Time, delay boo
 	return s.New(title, f, at.At(t), delay)
 }
 
+// AddAt 添加 At 类型的定时器
+//
+// 与 [Server.At] 相同，但是 t 为零值时返回 [InvalidError]。
+func (s *Server) AddAt(title localeutil.Stringer, f JobFunc, t time.Time, delay bool) (context.CancelFunc, error) {
+	scheduler, err := at.New(t)
+	if err != nil {
+		return nil, err
+	}
+	return s.Add(title, f, scheduler, delay)
+}
+
+// AddSpec 使用 kind:spec 格式的文本添加定时任务
+//
+// 具体格式可参考 [schedulers.Parse]，时区采用 [Server.Location]。
+// 默认可用的有 cron、every 和 at，其它类型需要引用相应的包。
+func (s *Server) AddSpec(title localeutil.Stringer, f JobFunc, text string, delay bool) (context.CancelFunc, error) {
+	scheduler, err := schedulers.Parse(text, s.Location())
+	if err != nil {
+		return nil, err
+	}
+	return s.Add(title, f, scheduler, delay)
+}
+
 // New 添加一个新的定时任务
 //
 // title 任务的简要描述；
 // delay 是否从任务执行完之后，才开始计算下个执行的时间点。
 // 返回一个取消当前任务的方法，同时会从列表中删除。
+// 参数错误时 panic，如果需要返回错误，可以使用 [Server.Add]。
 func (s *Server) New(title localeutil.Stringer, f JobFunc, scheduler Scheduler, delay bool) context.CancelFunc {
-	job := &Job{
-		s:     scheduler,
-		title: title,
-		f:     f,
-		delay: delay,
+	cancel, err := s.Add(title, f, scheduler, delay)
+	if err != nil {
+		panic(err)
 	}
+	return cancel
+}
+
+// Add 添加一个新的定时任务
+//
+// 与 [Server.New] 相同，但是 scheduler 为空时返回 [InvalidError] 而不是 panic。
+func (s *Server) Add(title localeutil.Stringer, f JobFunc, scheduler Scheduler, delay bool) (context.CancelFunc, error) {
+	return s.AddContext(title, wrap(f), scheduler, delay, 0)
+}
+
+// NewContext 添加一个可感知取消操作的定时任务
+//
+// timeout 为每次执行的超时时间，超时之后会取消传递给 f 的 ctx，0 表示不限制；
+// 其它参数与 [Server.New] 相同。
+func (s *Server) NewContext(title localeutil.Stringer, f ContextJobFunc, scheduler Scheduler, delay bool, timeout time.Duration) context.CancelFunc {
+	cancel, err := s.AddContext(title, f, scheduler, delay, timeout)
+	if err != nil {
+		panic(err)
+	}
+	return cancel
+}
+
+// AddContext 添加一个可感知取消操作的定时任务
+//
+// 与 [Server.NewContext] 相同，但是参数错误时返回 [InvalidError] 而不是 panic。
+func (s *Server) AddContext(title localeutil.Stringer, f ContextJobFunc, scheduler Scheduler, delay bool, timeout time.Duration) (context.CancelFunc, error) {
+	if scheduler == nil {
+		return nil, &InvalidError{Spec: "<nil>", Err: localeutil.Error("can not be empty")}
+	}
+	if timeout < 0 {
+		return nil, &InvalidError{Spec: timeout.String(), Err: localeutil.Error("invalid duration %s", timeout.String())}
+	}
+	job := newJob(title, f, scheduler, delay, timeout)
+
+	s.locker.Lock()
+	running, gen := s.running, s.gen
 	s.jobs = append(s.jobs, job)
+	s.locker.Unlock()
+
+	// 服务已经运行，由 Serve 初始化的任务可能已经完成，需要自行初始化。
+	// init 会调用用户的代码，不能在持有锁的情况下调用。
+	if running {
+		job.init(time.Now(), gen)
+		s.reschedule()
+	}
 
-	if s.running { // 服务已经运行，则需要触发调度任务。
-		job.init(time.Now())
-		s.schedule <- struct{}{} // 执行一次调度任务
+	unnotify := func() {}
+	if n, ok := scheduler.(schedulers.Notifier); ok { // 调度规则发生变化时重新计算
+		unnotify = n.Notify(func() {
+			if s.isRunning() {
+				job.reset(time.Now())
+				s.reschedule()
+			}
+		})
 	}
 
 	return func() {
+		unnotify()
+		job.cancel() // 同时取消正在执行的任务
+		s.locker.Lock()
 		s.jobs = slices.DeleteFunc(s.jobs, func(e *Job) bool { return e == job })
-	}
+		s.locker.Unlock()
+	}, nil
 }
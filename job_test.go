@@ -5,6 +5,7 @@
 package scheduled
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -52,51 +53,34 @@ func TestJob_run(t *testing.T) {
 	}
 
 	now := time.Now()
-	j := &Job{
-		title: localeutil.StringPhrase("succ"),
-		f:     succFunc,
-		s:     newTickerJob(time.Second, false),
-	}
-	j.init(now)
-	j.run(now, &defaultLogger{}, &defaultLogger{})
+	j := newJob(localeutil.StringPhrase("succ"), wrap(succFunc), newTickerJob(time.Second, false), false, 0)
+	j.init(now, 1)
+	j.run(context.Background(), now, &defaultLogger{}, &defaultLogger{})
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
 
 	now = time.Now()
-	j = &Job{
-		title: localeutil.StringPhrase("erro"),
-		f:     erroFunc,
-		s:     newTickerJob(time.Second, false),
-	}
-	j.init(now)
-	j.run(now, errlog, &defaultLogger{})
+	j = newJob(localeutil.StringPhrase("erro"), wrap(erroFunc), newTickerJob(time.Second, false), false, 0)
+	j.init(now, 1)
+	j.run(context.Background(), now, errlog, &defaultLogger{})
 	a.NotNil(j.Err()).
 		Equal(j.State(), Failed).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
 
 	now = time.Now()
-	j = &Job{
-		title: localeutil.StringPhrase("fail"),
-		f:     failFunc,
-		s:     newTickerJob(time.Second, false),
-	}
-	j.init(now)
-	j.run(now, errlog, errlog)
+	j = newJob(localeutil.StringPhrase("fail"), wrap(failFunc), newTickerJob(time.Second, false), false, 0)
+	j.init(now, 1)
+	j.run(context.Background(), now, errlog, errlog)
 	a.NotNil(j.Err()).
 		Equal(j.State(), Failed).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
 
 	// delay == true
 	now = time.Now()
-	j = &Job{
-		title: localeutil.StringPhrase("delay=true"),
-		f:     delayFunc,
-		s:     newTickerJob(time.Second, false),
-		delay: true,
-	}
-	j.init(now)
-	j.run(now, &defaultLogger{}, &defaultLogger{})
+	j = newJob(localeutil.StringPhrase("delay=true"), wrap(delayFunc), newTickerJob(time.Second, false), true, 0)
+	j.init(now, 1)
+	j.run(context.Background(), now, &defaultLogger{}, &defaultLogger{})
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		True(j.Delay()).
@@ -104,14 +88,9 @@ func TestJob_run(t *testing.T) {
 
 	// delay == false
 	now = time.Now()
-	j = &Job{
-		title: localeutil.StringPhrase("delay=false"),
-		f:     delayFunc,
-		s:     newTickerJob(time.Second, false),
-		delay: false,
-	}
-	j.init(now)
-	j.run(now, &defaultLogger{}, &defaultLogger{})
+	j = newJob(localeutil.StringPhrase("delay=false"), wrap(delayFunc), newTickerJob(time.Second, false), false, 0)
+	j.init(now, 1)
+	j.run(context.Background(), now, &defaultLogger{}, &defaultLogger{})
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		Empty(j.Prev()).
@@ -181,3 +160,124 @@ func TestServer_Cron(t *testing.T) {
 		srv.Cron(localeutil.StringPhrase("test"), nil, "* * * 3-7a * *", false)
 	})
 }
+
+func TestServer_Add(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+
+	var target *InvalidError
+
+	cancel, err := srv.AddCron(localeutil.StringPhrase("cron"), succFunc, "* * * 3-7a * *", false)
+	a.Error(err).Nil(cancel).
+		True(errors.As(err, &target)).
+		Equal(target.Spec, "* * * 3-7a * *")
+	cancel, err = srv.AddCron(localeutil.StringPhrase("cron"), succFunc, "* * * 3-7 * *", false)
+	a.NotError(err).NotNil(cancel)
+
+	cancel, err = srv.AddTick(localeutil.StringPhrase("tick"), succFunc, 0, false, false)
+	a.Error(err).Nil(cancel).True(errors.As(err, &target))
+	cancel, err = srv.AddTick(localeutil.StringPhrase("tick"), succFunc, time.Millisecond, false, false)
+	a.NotError(err).NotNil(cancel)
+
+	cancel, err = srv.AddTickAligned(localeutil.StringPhrase("aligned"), succFunc, -time.Second, false)
+	a.Error(err).Nil(cancel).True(errors.As(err, &target))
+	cancel, err = srv.AddTickAligned(localeutil.StringPhrase("aligned"), succFunc, time.Minute, false)
+	a.NotError(err).NotNil(cancel)
+
+	cancel, err = srv.AddAt(localeutil.StringPhrase("at"), succFunc, time.Time{}, false)
+	a.Error(err).Nil(cancel).True(errors.As(err, &target))
+	cancel, err = srv.AddAt(localeutil.StringPhrase("at"), succFunc, time.Now(), false)
+	a.NotError(err).NotNil(cancel)
+
+	cancel, err = srv.AddSpec(localeutil.StringPhrase("spec"), succFunc, "cron:* * * 3-7a * *", false)
+	a.Error(err).Nil(cancel).
+		True(errors.As(err, &target)).
+		Equal(target.Spec, "cron:* * * 3-7a * *")
+	cancel, err = srv.AddSpec(localeutil.StringPhrase("spec"), succFunc, "every:5m", false)
+	a.NotError(err).NotNil(cancel)
+
+	cancel, err = srv.Add(localeutil.StringPhrase("nil"), succFunc, nil, false)
+	a.Error(err).Nil(cancel).True(errors.As(err, &target))
+	a.Panic(func() {
+		srv.New(localeutil.StringPhrase("nil"), succFunc, nil, false)
+	})
+
+	a.Length(srv.Jobs(), 5)
+}
+
+// 任务执行期间，读取状态不应该被阻塞。
+func TestJob_run_status(t *testing.T) {
+	a := assert.New(t, false)
+
+	start := make(chan struct{})
+	exit := make(chan struct{})
+	f := func(time.Time) error {
+		close(start)
+		<-exit
+		return nil
+	}
+	j := newJob(localeutil.StringPhrase("block"), wrap(f), ticker.Tick(time.Second, false), true, 0)
+
+	now := time.Now()
+	j.init(now, 1)
+	j.calcState(now)
+	done := make(chan struct{})
+	go func() {
+		j.run(context.Background(), now, errlog, &defaultLogger{})
+		close(done)
+	}()
+	<-start
+
+	read := make(chan struct{})
+	go func() {
+		a.Equal(j.State(), Running).
+			Nil(j.Err()).
+			False(j.Next().IsZero()).
+			False(j.Prev().IsZero()).
+			False(j.waiting())
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-time.After(500 * time.Millisecond):
+		a.TB().Fatal("读取状态被阻塞")
+	}
+
+	close(exit)
+	<-done
+	a.Equal(j.State(), Stopped).Nil(j.Err())
+}
+
+func TestJob_run_context(t *testing.T) {
+	a := assert.New(t, false)
+
+	wait := func(ctx context.Context, _ time.Time) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	// 超时
+	j := newJob(localeutil.StringPhrase("timeout"), wait, ticker.Tick(time.Second, false), false, 100*time.Millisecond)
+	a.Equal(j.Timeout(), 100*time.Millisecond)
+	now := time.Now()
+	j.init(now, 1)
+	j.run(context.Background(), now, errlog, &defaultLogger{})
+	a.ErrorIs(j.Err(), context.DeadlineExceeded).
+		Equal(j.State(), Failed).
+		True(time.Since(now) < time.Second)
+
+	// 取消 Serve 的 ctx
+	j = newJob(localeutil.StringPhrase("serve"), wait, ticker.Tick(time.Second, false), false, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	j.init(now, 1)
+	j.run(ctx, now, errlog, &defaultLogger{})
+	a.ErrorIs(j.Err(), context.Canceled).True(j.waiting())
+
+	// 取消任务
+	j = newJob(localeutil.StringPhrase("cancel"), wait, ticker.Tick(time.Second, false), false, 0)
+	time.AfterFunc(100*time.Millisecond, j.cancel)
+	j.init(now, 1)
+	j.run(context.Background(), now, errlog, &defaultLogger{})
+	a.ErrorIs(j.Err(), context.Canceled).False(j.waiting())
+}
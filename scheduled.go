@@ -10,14 +10,22 @@
 // 通过 scheduled 可以实现管理类似 linux 中 crontab 功能的计划任务功能。
 // 当然功能并不止于此，用户可以实现自己的调度算法，定制任务的启动机制。
 //
-// 目前 scheduled 内置了以下三种算法：
+// 目前 scheduled 内置了以下算法：
 //   - cron 实现了 crontab 中的大部分语法功能；
-//   - at 在固定的时间点执行一次任务；
-//   - ticker 以固定的时间段执行任务，与 [time.Ticker] 相同。
+//   - at 在固定的时间点执行一次任务，也可以解析类似于 at 指令的时间描述；
+//   - ticker 以固定的时间段执行任务，与 [time.Ticker] 相同，也可以与时钟对齐；
+//   - rrule 实现了 RFC 5545 中的 RRULE 重复规则；
+//   - calendar 实现了 systemd 中的 OnCalendar 表达式；
+//   - repeat 实现了 ISO 8601 中的重复时间间隔；
+//   - interval 以日、周或是月为单位按日历间隔执行任务；
+//   - solar 在日出、日落等太阳事件发生时执行任务；
+//   - lunar 按农历日期执行任务；
+//   - natural 解析中英文的自然语言描述，比如 every weekday at 9am 或是 每周一早上9点。
 package scheduled
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/issue9/localeutil"
 
@@ -34,6 +42,7 @@ const (
 type (
 	Scheduler     = schedulers.Scheduler
 	SchedulerFunc = schedulers.SchedulerFunc
+	InvalidError  = schedulers.InvalidError
 
 	// Logger 日志接口
 	//
@@ -48,6 +57,12 @@ type (
 
 	State int8
 
+	// ShutdownError [Server.Shutdown] 超时时返回的错误
+	ShutdownError struct {
+		Jobs []*Job // 超时时依然在执行的任务
+		Err  error  // 超时的原因，一般为 ctx.Err()
+	}
+
 	defaultLogger struct{}
 
 	termLogger struct {
@@ -55,6 +70,9 @@ type (
 	}
 )
 
+// ErrServerClosed 调用 [Server.Shutdown] 之后 [Server.Serve] 返回的错误
+var ErrServerClosed = localeutil.Error("server closed")
+
 var (
 	stateStringMap = map[State]string{
 		Stopped: "stopped",
@@ -80,6 +98,18 @@ func (l *termLogger) Error(err error) { fmt.Println(err) }
 
 func (l *termLogger) LocaleString(s localeutil.Stringer) { fmt.Println(s.LocaleString(l.p)) }
 
+func (err *ShutdownError) Error() string { return err.LocaleString(nil) }
+
+func (err *ShutdownError) LocaleString(p *localeutil.Printer) string {
+	titles := make([]string, 0, len(err.Jobs))
+	for _, j := range err.Jobs {
+		titles = append(titles, j.Title().LocaleString(p))
+	}
+	return localeutil.Phrase("jobs still running after shutdown: %s", strings.Join(titles, ", ")).LocaleString(p)
+}
+
+func (err *ShutdownError) Unwrap() error { return err.Err }
+
 func (s State) String() string {
 	v, found := stateStringMap[s]
 	if !found {
@@ -8,9 +8,33 @@ package at
 import (
 	"time"
 
+	"github.com/issue9/localeutil"
+
 	"github.com/issue9/scheduled/schedulers"
 )
 
+func init() {
+	schedulers.Register("at", func(spec string, loc *time.Location) (schedulers.Scheduler, error) {
+		t, err := time.Parse(time.RFC3339Nano, spec)
+		if err != nil {
+			if t, err = time.ParseInLocation(time.DateTime, spec, loc); err != nil {
+				return nil, localeutil.Error("invalid time %s", spec)
+			}
+		}
+		return New(t)
+	})
+}
+
+// New 返回只在指定时间执行一次的调度器
+//
+// t 为零值时返回 [schedulers.InvalidError]。
+func New(t time.Time) (schedulers.Scheduler, error) {
+	if t.IsZero() {
+		return nil, &schedulers.InvalidError{Spec: t.String(), Err: localeutil.Error("can not be empty")}
+	}
+	return At(t), nil
+}
+
 // At 返回只在指定时间执行一次的调度器
 func At(t time.Time) schedulers.Scheduler {
 	return schedulers.SchedulerFunc(func(time.Time) time.Time {
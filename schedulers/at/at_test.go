@@ -5,10 +5,13 @@
 package at
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
 )
 
 func TestAt(t *testing.T) {
@@ -26,3 +29,34 @@ func TestAt(t *testing.T) {
 	a.True(s.Next(now).After(now)).
 		True(s.Next(now).IsZero()) // 多次获取，返回零值
 }
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := New(time.Time{})
+	a.Error(err).Nil(s)
+	var target *schedulers.InvalidError
+	a.True(errors.As(err, &target))
+
+	now := time.Now()
+	s, err = New(now)
+	a.NotError(err).NotNil(s).
+		Equal(s.Next(now), now)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	s, err := schedulers.Parse("at:2026-01-01T09:00:00Z", loc)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "at:2026-01-01T09:00:00Z").
+		True(s.Next(time.Time{}).Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)))
+
+	s, err = schedulers.Parse("at:2026-01-01 09:00:00", loc)
+	a.NotError(err).NotNil(s).
+		Equal(s.Next(time.Time{}), time.Date(2026, 1, 1, 9, 0, 0, 0, loc))
+
+	s, err = schedulers.Parse("at:tomorrow", loc)
+	a.Error(err).Nil(s)
+}
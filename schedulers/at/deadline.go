@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import "time"
+
+// Deadline 在截止时间 deadline 之前的多个时间点执行的调度器
+//
+// leads 为相对于 deadline 的提前量，比如 24*time.Hour 表示截止前一天，
+// 为 0 表示 deadline 本身，顺序和重复项不影响结果。
+// 时间点按从早到晚的顺序返回，已经过去的时间点会被跳过，全部执行完之后返回零值。
+func Deadline(deadline time.Time, leads ...time.Duration) *Set {
+	times := make([]time.Time, 0, len(leads))
+	for _, lead := range leads {
+		times = append(times, deadline.Add(-lead))
+	}
+	return NewSet(times...)
+}
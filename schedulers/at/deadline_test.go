@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestDeadline(t *testing.T) {
+	a := assert.New(t, false)
+	deadline := time.Date(2026, 12, 31, 18, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	s := Deadline(deadline, time.Hour, 30*day, day, 7*day, day)
+	a.Equal(s.Len(), 4)
+
+	last := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, want := range []time.Time{
+		time.Date(2026, 12, 1, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 24, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 30, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC),
+	} {
+		last = s.Next(last)
+		a.Equal(last, want)
+	}
+	a.True(s.Next(last).IsZero())
+
+	// 跳过已经过去的时间点
+	a.Equal(s.Next(time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)), time.Date(2026, 12, 30, 18, 0, 0, 0, time.UTC)).
+		True(s.Next(deadline).IsZero())
+
+	// 包含截止时间本身
+	s = Deadline(deadline, 0, time.Hour)
+	a.Equal(s.Next(deadline.Add(-time.Minute)), deadline)
+
+	a.True(Deadline(deadline).Next(last).IsZero())
+}
@@ -0,0 +1,419 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+type unit int8
+
+const (
+	unitMinute unit = iota
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+var units = map[string]unit{
+	"minute": unitMinute, "minutes": unitMinute, "min": unitMinute, "mins": unitMinute,
+	"hour": unitHour, "hours": unitHour,
+	"day": unitDay, "days": unitDay,
+	"week": unitWeek, "weeks": unitWeek,
+	"month": unitMonth, "months": unitMonth,
+	"year": unitYear, "years": unitYear,
+}
+
+type parser struct {
+	spec   string
+	tokens []string
+	pos    int
+
+	now     time.Time
+	hasTime bool
+	hour    int
+	minute  int
+
+	// 日期
+	hasDate bool
+	year    int // 为 0 表示未指定年份
+	month   time.Month
+	day     int
+
+	hasWeekday bool
+	weekday    time.Weekday
+	next       bool // next weekday
+
+	isNow bool
+	incr  [unitYear + 1]int
+}
+
+// Parse 将类似于 at 指令的时间描述转换为只执行一次的 [schedulers.Scheduler]
+//
+// 具体的语法可参考 [ParseTime]。
+func Parse(spec string, now time.Time, loc *time.Location) (schedulers.Scheduler, error) {
+	t, err := ParseTime(spec, now, loc)
+	if err != nil {
+		return nil, err
+	}
+	return At(t), nil
+}
+
+// ParseTime 将类似于 at 指令的时间描述转换为具体的时间
+//
+// now 为参考时间，所有相对的描述都以此为基准；
+// loc 为时区，为 nil 时表示 [time.Local]。
+// spec 不区分大小写，由时间、日期以及增量三部分组成，各部分均可省略，但不能全部省略：
+//
+//   - 时间：10:00、1000、10am、10:30pm、midnight、noon 和 teatime(16:00)；
+//   - 日期：today、tomorrow、friday、next friday、jul 4、july 4 2026、
+//     2026-07-04、07/04/2026 和 04.07.2026；
+//   - 增量：now、+ 2 hours、next week 等，单位可以是 minute、hour、day、week、month 和 year。
+//
+// 比如 now + 2 hours、tomorrow 10:00、noon next friday、teatime 等。
+//
+// 仅指定时间且该时间已经过去，则为明天的该时间；
+// 仅指定日期则使用 now 的时间部分；
+// 未指定年份的日期如果已经过去，则为下一年的该日期。
+// 如果最终的时间早于 now，返回错误。
+func ParseTime(spec string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	p := &parser{spec: spec, tokens: tokenize(spec), now: now.In(loc)}
+	if len(p.tokens) == 0 {
+		return time.Time{}, p.syntaxError()
+	}
+
+	for p.pos < len(p.tokens) {
+		if err := p.parseToken(); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	t := p.time()
+	if t.Before(now) {
+		return time.Time{}, localeutil.Error("%s is in the past", spec)
+	}
+	return t, nil
+}
+
+func tokenize(spec string) []string {
+	tokens := make([]string, 0, 6)
+	runes := []rune(strings.ToLower(spec))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			i++
+		case r == '+':
+			tokens = append(tokens, "+")
+			i++
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(":/.-", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+func (p *parser) syntaxError() error { return localeutil.Error("at syntax error %s", p.spec) }
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseToken() error {
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token {
+	case "now":
+		if p.isNow || p.hasTime || p.hasDate || p.hasWeekday {
+			return p.syntaxError()
+		}
+		p.isNow = true
+		return nil
+	case "+":
+		return p.parseIncrement()
+	case "next":
+		if wd, found := weekdays[p.peek()]; found {
+			p.pos++
+			p.next = true
+			return p.setWeekday(wd)
+		}
+		if u, found := units[p.peek()]; found {
+			p.pos++
+			p.incr[u]++
+			return nil
+		}
+		return p.syntaxError()
+	case "today":
+		return p.setDate(p.now.Year(), p.now.Month(), p.now.Day())
+	case "tomorrow":
+		t := p.now.AddDate(0, 0, 1)
+		return p.setDate(t.Year(), t.Month(), t.Day())
+	case "midnight":
+		return p.setTime(0, 0)
+	case "noon":
+		return p.setTime(12, 0)
+	case "teatime":
+		return p.setTime(16, 0)
+	}
+
+	if wd, found := weekdays[token]; found {
+		return p.setWeekday(wd)
+	}
+
+	if m, found := months[token]; found {
+		return p.parseMonthDay(m)
+	}
+
+	if token[0] >= '0' && token[0] <= '9' {
+		if strings.ContainsAny(token, "/.-") {
+			return p.parseDate(token)
+		}
+		return p.parseTime(token)
+	}
+
+	return p.syntaxError()
+}
+
+// + n unit
+func (p *parser) parseIncrement() error {
+	n, err := strconv.Atoi(p.peek())
+	if err != nil || n < 0 {
+		return p.syntaxError()
+	}
+	p.pos++
+
+	u, found := units[p.peek()]
+	if !found {
+		return p.syntaxError()
+	}
+	p.pos++
+	p.incr[u] += n
+	return nil
+}
+
+// month day [year]
+func (p *parser) parseMonthDay(m time.Month) error {
+	day, err := strconv.Atoi(p.peek())
+	if err != nil {
+		return p.syntaxError()
+	}
+	p.pos++
+
+	year := 0
+	if next := p.peek(); len(next) == 4 {
+		if year, err = strconv.Atoi(next); err != nil {
+			return p.syntaxError()
+		}
+		p.pos++
+	}
+	return p.setDate(year, m, day)
+}
+
+// 2006-01-02、01/02/2006 和 02.01.2006
+func (p *parser) parseDate(token string) error {
+	var layout string
+	switch {
+	case strings.Count(token, "-") == 2:
+		layout = "2006-1-2"
+	case strings.Count(token, "/") == 2:
+		layout = "1/2/2006"
+	case strings.Count(token, ".") == 2:
+		layout = "2.1.2006"
+	default:
+		return p.syntaxError()
+	}
+
+	t, err := time.Parse(layout, token)
+	if err != nil {
+		return p.syntaxError()
+	}
+	return p.setDate(t.Year(), t.Month(), t.Day())
+}
+
+// 10:00、1000、10 am 和 10:30 pm
+func (p *parser) parseTime(token string) error {
+	var hour, minute int
+	var err error
+
+	if h, m, found := strings.Cut(token, ":"); found {
+		if hour, err = strconv.Atoi(h); err != nil || len(h) > 2 {
+			return p.syntaxError()
+		}
+		if minute, err = strconv.Atoi(m); err != nil || len(m) != 2 {
+			return p.syntaxError()
+		}
+	} else {
+		switch len(token) {
+		case 1, 2:
+			hour, _ = strconv.Atoi(token)
+			minute = 0
+			if s := p.peek(); s != "am" && s != "pm" { // 单独的数字必须指定 am 或是 pm
+				return p.syntaxError()
+			}
+		case 3, 4:
+			hour, _ = strconv.Atoi(token[:len(token)-2])
+			minute, _ = strconv.Atoi(token[len(token)-2:])
+		default:
+			return p.syntaxError()
+		}
+	}
+
+	switch p.peek() {
+	case "am":
+		p.pos++
+		if hour < 1 || hour > 12 {
+			return p.syntaxError()
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		p.pos++
+		if hour < 1 || hour > 12 {
+			return p.syntaxError()
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return localeutil.Error("invalid time %s", token)
+	}
+	return p.setTime(hour, minute)
+}
+
+func (p *parser) setTime(hour, minute int) error {
+	if p.hasTime || p.isNow {
+		return p.syntaxError()
+	}
+	p.hasTime = true
+	p.hour = hour
+	p.minute = minute
+	return nil
+}
+
+func (p *parser) setDate(year int, month time.Month, day int) error {
+	if p.hasDate || p.hasWeekday || p.isNow {
+		return p.syntaxError()
+	}
+
+	y := year
+	if y == 0 {
+		y = 2000 // 闰年，允许 2 月 29 日。
+	}
+	if day < 1 || day > time.Date(y, month+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return localeutil.Error("invalid date %s", p.spec)
+	}
+
+	p.hasDate = true
+	p.year = year
+	p.month = month
+	p.day = day
+	return nil
+}
+
+func (p *parser) setWeekday(wd time.Weekday) error {
+	if p.hasDate || p.hasWeekday || p.isNow {
+		return p.syntaxError()
+	}
+	p.hasWeekday = true
+	p.weekday = wd
+	return nil
+}
+
+func (p *parser) time() time.Time {
+	now := p.now
+	loc := now.Location()
+
+	// 未指定时间部分时，使用 now 的时间。
+	hour, minute, sec, nsec := now.Hour(), now.Minute(), now.Second(), now.Nanosecond()
+	if p.hasTime {
+		hour, minute, sec, nsec = p.hour, p.minute, 0, 0
+	}
+
+	t := now
+	switch {
+	case p.hasDate:
+		year := p.year
+		if year == 0 {
+			year = now.Year()
+		}
+		t = time.Date(year, p.month, p.day, hour, minute, sec, nsec, loc)
+		for p.year == 0 && (t.Before(now) || t.Day() != p.day) { // 已经过去或是不存在的 2 月 29 日
+			year++
+			t = time.Date(year, p.month, p.day, hour, minute, sec, nsec, loc)
+		}
+	case p.hasWeekday:
+		days := (int(p.weekday) - int(now.Weekday()) + 7) % 7
+		t = time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, sec, nsec, loc)
+		if (days == 0 && p.next) || t.Before(now) {
+			t = t.AddDate(0, 0, 7)
+		}
+	case p.hasTime:
+		t = time.Date(now.Year(), now.Month(), now.Day(), hour, minute, sec, nsec, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+
+	t = t.AddDate(p.incr[unitYear], p.incr[unitMonth], p.incr[unitWeek]*7+p.incr[unitDay])
+	return t.Add(time.Duration(p.incr[unitHour])*time.Hour + time.Duration(p.incr[unitMinute])*time.Minute)
+}
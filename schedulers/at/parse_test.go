@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestParseTime(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2026, 10, 19, 14, 25, 30, 0, loc) // 周一
+
+	data := map[string]time.Time{
+		"now":               time.Date(2026, 10, 19, 14, 25, 30, 0, loc),
+		"now + 2 hours":     time.Date(2026, 10, 19, 16, 25, 30, 0, loc),
+		"NOW+2hours":        time.Date(2026, 10, 19, 16, 25, 30, 0, loc),
+		"now + 30 minutes":  time.Date(2026, 10, 19, 14, 55, 30, 0, loc),
+		"+ 1 day":           time.Date(2026, 10, 20, 14, 25, 30, 0, loc),
+		"now + 1 month":     time.Date(2026, 11, 19, 14, 25, 30, 0, loc),
+		"next week":         time.Date(2026, 10, 26, 14, 25, 30, 0, loc),
+		"tomorrow 10:00":    time.Date(2026, 10, 20, 10, 0, 0, 0, loc),
+		"10:00 tomorrow":    time.Date(2026, 10, 20, 10, 0, 0, 0, loc),
+		"10:00":             time.Date(2026, 10, 20, 10, 0, 0, 0, loc),
+		"15:00":             time.Date(2026, 10, 19, 15, 0, 0, 0, loc),
+		"1500":              time.Date(2026, 10, 19, 15, 0, 0, 0, loc),
+		"3pm":               time.Date(2026, 10, 19, 15, 0, 0, 0, loc),
+		"3:30 PM":           time.Date(2026, 10, 19, 15, 30, 0, 0, loc),
+		"12am":              time.Date(2026, 10, 20, 0, 0, 0, 0, loc),
+		"12pm":              time.Date(2026, 10, 20, 12, 0, 0, 0, loc),
+		"midnight":          time.Date(2026, 10, 20, 0, 0, 0, 0, loc),
+		"noon":              time.Date(2026, 10, 20, 12, 0, 0, 0, loc),
+		"teatime":           time.Date(2026, 10, 19, 16, 0, 0, 0, loc),
+		"teatime today":     time.Date(2026, 10, 19, 16, 0, 0, 0, loc),
+		"noon next friday":  time.Date(2026, 10, 23, 12, 0, 0, 0, loc),
+		"noon friday":       time.Date(2026, 10, 23, 12, 0, 0, 0, loc),
+		"monday 15:00":      time.Date(2026, 10, 19, 15, 0, 0, 0, loc),
+		"monday 10:00":      time.Date(2026, 10, 26, 10, 0, 0, 0, loc),
+		"next mon 15:00":    time.Date(2026, 10, 26, 15, 0, 0, 0, loc),
+		"10am jul 4":        time.Date(2027, 7, 4, 10, 0, 0, 0, loc),
+		"10am december 25":  time.Date(2026, 12, 25, 10, 0, 0, 0, loc),
+		"noon july 4 2030":  time.Date(2030, 7, 4, 12, 0, 0, 0, loc),
+		"feb 29":            time.Date(2028, 2, 29, 14, 25, 30, 0, loc),
+		"10:00 2026-12-01":  time.Date(2026, 12, 1, 10, 0, 0, 0, loc),
+		"10:00 12/01/2026":  time.Date(2026, 12, 1, 10, 0, 0, 0, loc),
+		"10:00 01.12.2026":  time.Date(2026, 12, 1, 10, 0, 0, 0, loc),
+		"teatime + 2 days":  time.Date(2026, 10, 21, 16, 0, 0, 0, loc),
+		"tomorrow + 1 week": time.Date(2026, 10, 27, 14, 25, 30, 0, loc),
+	}
+	for spec, want := range data {
+		got, err := ParseTime(spec, now, loc)
+		a.NotError(err, spec).Equal(got, want, spec)
+	}
+
+	for _, spec := range []string{
+		"",
+		"later",
+		"now now",
+		"now 10:00",
+		"10:00 now",
+		"10:00 11:00",
+		"today tomorrow",
+		"friday today",
+		"+",
+		"+ 2",
+		"+ x hours",
+		"+ 2 fortnights",
+		"next",
+		"next noon",
+		"10",
+		"13pm",
+		"0am",
+		"25:00",
+		"10:60",
+		"10:0",
+		"12345",
+		"feb 30",
+		"feb",
+		"2026-13-01",
+		"2026/12",
+		"10:00 2020-01-01",
+		"now !",
+	} {
+		got, err := ParseTime(spec, now, loc)
+		a.Error(err, spec).True(got.IsZero(), spec)
+	}
+
+	// loc 为 nil
+	got, err := ParseTime("now", now, nil)
+	a.NotError(err).Equal(got.Location(), time.Local).True(got.Equal(now))
+}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+	now := time.Date(2026, 10, 19, 14, 25, 30, 0, time.UTC)
+
+	s, err := Parse("teatime", now, time.UTC)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC)).
+		True(s.Next(now).IsZero())
+
+	s, err = Parse("yesterday", now, time.UTC)
+	a.Error(err).Nil(s)
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Set 在一组时间点上依次执行的调度器
+//
+// 时间点会被去重并排序，可以在运行过程中通过 [Set.Add] 和 [Set.Remove]
+// 修改，所有方法都是协程安全的。
+//
+// Set 实现了 [schedulers.Notifier]，添加到 [scheduled.Server] 之后，
+// 修改时间点也会同步修改任务的下一次执行时间。
+//
+// [scheduled.Server]: https://pkg.go.dev/github.com/issue9/scheduled#Server
+type Set struct {
+	locker sync.RWMutex
+	times  []time.Time // 已排序且不重复
+	hooks  map[int]func()
+	id     int
+}
+
+var _ schedulers.Notifier = &Set{}
+
+// NewSet 声明 [Set] 对象
+func NewSet(t ...time.Time) *Set {
+	s := &Set{
+		times: make([]time.Time, 0, len(t)),
+		hooks: make(map[int]func(), 1),
+	}
+	s.Add(t...)
+	return s
+}
+
+// Add 添加时间点
+//
+// 已经存在的时间点会被忽略，零值也会被忽略。
+// 有新的时间点加入时，会调用由 [Set.Notify] 注册的函数。
+func (s *Set) Add(t ...time.Time) {
+	s.locker.Lock()
+	changed := false
+	for _, item := range t {
+		if item.IsZero() {
+			continue
+		}
+		if index, found := s.search(item); !found {
+			s.times = slices.Insert(s.times, index, item)
+			changed = true
+		}
+	}
+	s.locker.Unlock()
+
+	if changed {
+		s.notify()
+	}
+}
+
+// Remove 删除时间点
+//
+// 不存在的时间点会被忽略。
+// 有时间点被删除时，会调用由 [Set.Notify] 注册的函数。
+func (s *Set) Remove(t ...time.Time) {
+	s.locker.Lock()
+	changed := false
+	for _, item := range t {
+		if index, found := s.search(item); found {
+			s.times = slices.Delete(s.times, index, index+1)
+			changed = true
+		}
+	}
+	s.locker.Unlock()
+
+	if changed {
+		s.notify()
+	}
+}
+
+// Notify 注册在时间点发生变化之后调用的函数
+//
+// 返回值用于取消注册。
+func (s *Set) Notify(f func()) (cancel func()) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.hooks == nil { // 未通过 NewSet 声明
+		s.hooks = make(map[int]func(), 1)
+	}
+	s.id++
+	id := s.id
+	s.hooks[id] = f
+
+	return func() {
+		s.locker.Lock()
+		delete(s.hooks, id)
+		s.locker.Unlock()
+	}
+}
+
+// 调用所有注册的函数，调用时不能持有锁。
+func (s *Set) notify() {
+	s.locker.RLock()
+	hooks := make([]func(), 0, len(s.hooks))
+	for _, f := range s.hooks {
+		hooks = append(hooks, f)
+	}
+	s.locker.RUnlock()
+
+	for _, f := range hooks {
+		f()
+	}
+}
+
+// Times 返回所有的时间点
+func (s *Set) Times() []time.Time {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	return slices.Clone(s.times)
+}
+
+// Len 时间点的数量
+func (s *Set) Len() int {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	return len(s.times)
+}
+
+// Next 返回 last 之后的第一个时间点，如果不存在，返回零值。
+func (s *Set) Next(last time.Time) time.Time {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+
+	index, found := s.search(last)
+	if found {
+		index++
+	}
+	if index < len(s.times) {
+		return s.times[index]
+	}
+	return time.Time{}
+}
+
+func (s *Set) search(t time.Time) (int, bool) {
+	return slices.BinarySearchFunc(s.times, t, func(e, t time.Time) int { return e.Compare(t) })
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package at
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestSet(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	t1 := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	t2 := time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC)
+	t3 := time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC)
+
+	s := NewSet(t3, t1, t2, t1.In(loc), time.Time{})
+	a.Equal(s.Len(), 3).
+		Equal(s.Times(), []time.Time{t1, t2, t3})
+
+	last := t1.Add(-time.Hour)
+	for _, want := range []time.Time{t1, t2, t3} {
+		last = s.Next(last)
+		a.Equal(last, want)
+	}
+	a.True(s.Next(last).IsZero()).
+		Equal(s.Next(t1.Add(time.Hour)), t2)
+
+	// Add
+	t4 := time.Date(2026, 1, 4, 8, 0, 0, 0, time.UTC)
+	s.Add(t4, t2)
+	a.Equal(s.Len(), 4).
+		Equal(s.Next(t3), t4)
+
+	// Remove
+	s.Remove(t2, t2.Add(time.Second))
+	a.Equal(s.Times(), []time.Time{t1, t3, t4}).
+		Equal(s.Next(t1), t3)
+
+	// Times 返回的是副本
+	s.Times()[0] = t4
+	a.Equal(s.Next(time.Time{}), t1)
+
+	a.True(NewSet().Next(time.Time{}).IsZero())
+}
+
+func TestSet_Notify(t *testing.T) {
+	a := assert.New(t, false)
+	t1 := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	s := NewSet()
+	var count int
+	cancel := s.Notify(func() {
+		count++
+		s.Times() // 调用时未持有锁，不会死锁。
+	})
+
+	s.Add(t1)
+	a.Equal(count, 1)
+	s.Add(t1, time.Time{}) // 没有变化
+	a.Equal(count, 1)
+	s.Remove(t1.Add(time.Hour)) // 没有变化
+	a.Equal(count, 1)
+	s.Remove(t1)
+	a.Equal(count, 2)
+
+	cancel()
+	s.Add(t1)
+	a.Equal(count, 2)
+
+	// 零值
+	zero := &Set{}
+	zero.Notify(func() { count++ })
+	zero.Add(t1)
+	a.Equal(count, 3)
+}
+
+func TestSet_concurrent(t *testing.T) {
+	a := assert.New(t, false)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSet()
+
+	wg := &sync.WaitGroup{}
+	for i := range 100 {
+		wg.Go(func() {
+			t := start.Add(time.Duration(i) * time.Minute)
+			s.Add(t, t.Add(24*time.Hour))
+			s.Next(t)
+			s.Remove(t.Add(24 * time.Hour))
+		})
+	}
+	wg.Wait()
+
+	a.Equal(s.Len(), 100)
+	times := s.Times()
+	for i, t := range times {
+		a.Equal(t, start.Add(time.Duration(i)*time.Minute))
+	}
+}
@@ -0,0 +1,462 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package calendar 实现了 systemd 中 OnCalendar 表达式的 [schedulers.Scheduler] 接口
+//
+// 具体语法可参考 [systemd.time] 中的 Calendar Events 一节。
+//
+// [systemd.time]: https://www.freedesktop.org/software/systemd/man/latest/systemd.time.html#Calendar%20Events
+package calendar
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func init() {
+	schedulers.Register("calendar", func(spec string, loc *time.Location) (schedulers.Scheduler, error) {
+		s, err := Parse(spec, loc)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+}
+
+// 表示 Event.fields 中各个元素的索引值
+const (
+	yearIndex = iota
+	monthIndex
+	dayIndex
+	hourIndex
+	minuteIndex
+	secondIndex
+	indexSize
+)
+
+// 常用的便捷指令
+var shorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+}
+
+var bounds = []bound{
+	{min: 1970, max: 2199}, // yearIndex
+	{min: 1, max: 12},      // monthIndex
+	{min: 1, max: 31},      // dayIndex
+	{min: 0, max: 23},      // hourIndex
+	{min: 0, max: 59},      // minuteIndex
+	{min: 0, max: 59},      // secondIndex
+}
+
+// 以周一作为第一天，与 systemd 的输出保持一致。
+var weekdayNames = []weekdayName{
+	{"Mon", "monday", time.Monday},
+	{"Tue", "tuesday", time.Tuesday},
+	{"Wed", "wednesday", time.Wednesday},
+	{"Thu", "thursday", time.Thursday},
+	{"Fri", "friday", time.Friday},
+	{"Sat", "saturday", time.Saturday},
+	{"Sun", "sunday", time.Sunday},
+}
+
+type (
+	bound struct{ min, max int }
+
+	weekdayName struct {
+		short, long string
+		day         time.Weekday
+	}
+
+	// 表示字段中以逗号分隔的一项
+	//
+	// stop 小于 0 表示不是范围，repeat 为 0 表示不重复。
+	component struct{ start, stop, repeat int }
+
+	// 字段的内容，nil 表示 *。
+	field []component
+
+	// Event 表示 OnCalendar 表达式
+	Event struct {
+		weekdays   [7]bool // 以 time.Weekday 为索引
+		hasWeekday bool
+		fields     []field
+		endOfMonth bool // 日期是否以 ~ 表示从月末开始计算
+		loc        *time.Location
+		tz         string // 表达式中指定的时区名称
+	}
+)
+
+// Parse 解析 OnCalendar 表达式
+//
+// 格式为：
+//
+//	[星期] [年-月-日] [时:分[:秒]] [时区]
+//
+// 比如 Mon..Fri *-*-* 09:00:00、*-*-01 00:00:00、*:0/15 和 *-02~01 等，
+// 也支持 minutely、hourly、daily、weekly、monthly、yearly、quarterly 和 semiannually 等便捷指令。
+//
+// 如果表达式中没有指定时区，则采用 loc。
+func Parse(spec string, loc *time.Location) (*Event, error) {
+	tokens := strings.Fields(spec)
+	if len(tokens) == 0 {
+		return nil, syntaxError(localeutil.Phrase("can not be empty"))
+	}
+
+	if s, found := shorthands[strings.ToLower(tokens[0])]; found {
+		tokens = append(strings.Fields(s), tokens[1:]...)
+	}
+
+	e := &Event{
+		fields: []field{nil, nil, nil, {{start: 0, stop: -1}}, {{start: 0, stop: -1}}, {{start: 0, stop: -1}}},
+		loc:    loc,
+	}
+
+	var err error
+	if c := tokens[0][0]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		if err = e.parseWeekdays(tokens[0]); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 && strings.ContainsAny(tokens[0], "-~") {
+		if err = e.parseDate(tokens[0]); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 && strings.ContainsRune(tokens[0], ':') {
+		if err = e.parseTime(tokens[0]); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+
+	switch len(tokens) {
+	case 0:
+	case 1:
+		if e.loc, err = time.LoadLocation(tokens[0]); err != nil {
+			return nil, syntaxError(localeutil.Phrase("invalid timezone %s", tokens[0]))
+		}
+		e.tz = tokens[0]
+	default:
+		return nil, syntaxError(localeutil.Phrase("incorrect length"))
+	}
+
+	if e.loc == nil {
+		e.loc = time.Local
+	}
+
+	return e, nil
+}
+
+func (e *Event) parseWeekdays(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		if item == "" { // 允许末尾的逗号，比如 Wed, 17:48
+			continue
+		}
+
+		first, last, found := strings.Cut(item, "..")
+		if !found {
+			first, last, found = strings.Cut(item, "-")
+		}
+
+		start := weekdayIndex(first)
+		if start < 0 {
+			return syntaxError(localeutil.Phrase("invalid weekday %s", item))
+		}
+
+		stop := start
+		if found {
+			if stop = weekdayIndex(last); stop < start {
+				return syntaxError(localeutil.Phrase("invalid weekday %s", item))
+			}
+		}
+
+		for i := start; i <= stop; i++ {
+			e.weekdays[weekdayNames[i].day] = true
+		}
+	}
+
+	e.hasWeekday = true
+	return nil
+}
+
+// 返回 name 在 weekdayNames 中的索引
+func weekdayIndex(name string) int {
+	return slices.IndexFunc(weekdayNames, func(w weekdayName) bool {
+		return strings.EqualFold(w.short, name) || strings.EqualFold(w.long, name)
+	})
+}
+
+// 解析 [年-]月-日 或是 [年-]月~日
+func (e *Event) parseDate(s string) error {
+	index := strings.LastIndexAny(s, "-~")
+	e.endOfMonth = s[index] == '~'
+
+	day, err := parseField(s[index+1:], dayIndex)
+	if err != nil {
+		return err
+	}
+	e.fields[dayIndex] = day
+
+	ym := s[:index]
+	if strings.ContainsRune(ym, '~') {
+		return syntaxError(localeutil.Phrase("invalid date %s", s))
+	}
+
+	month := ym
+	if y, m, found := strings.Cut(ym, "-"); found {
+		if e.fields[yearIndex], err = parseField(y, yearIndex); err != nil {
+			return err
+		}
+		month = m
+	}
+	e.fields[monthIndex], err = parseField(month, monthIndex)
+	return err
+}
+
+// 解析 时:分[:秒]
+func (e *Event) parseTime(s string) error {
+	fs := strings.Split(s, ":")
+	if len(fs) < 2 || len(fs) > 3 {
+		return syntaxError(localeutil.Phrase("invalid time %s", s))
+	}
+
+	for i, f := range fs {
+		vals, err := parseField(f, hourIndex+i)
+		if err != nil {
+			return err
+		}
+		e.fields[hourIndex+i] = vals
+	}
+	return nil
+}
+
+// 解析单个字段
+//
+// 可以是以下格式的组合：
+//
+//	*
+//	n
+//	n1..n2
+//	n/step
+//	n1..n2/step
+//	*/step
+//	n1,n2
+func parseField(s string, typ int) (field, error) {
+	if s == "*" {
+		return nil, nil
+	}
+
+	b := bounds[typ]
+	items := strings.Split(s, ",")
+	ret := make(field, 0, len(items))
+	for _, item := range items {
+		c := component{stop: -1}
+
+		base, repeat, hasRepeat := strings.Cut(item, "/")
+		if hasRepeat {
+			n, err := strconv.Atoi(repeat)
+			if err != nil || n <= 0 {
+				return nil, syntaxError(localeutil.Phrase("invalid value %s", item))
+			}
+			c.repeat = n
+		}
+
+		if base == "*" && hasRepeat {
+			c.start = b.min
+		} else {
+			first, last, isRange := strings.Cut(base, "..")
+
+			var err error
+			if c.start, err = atoi(first, typ); err != nil {
+				return nil, syntaxError(localeutil.Phrase("invalid value %s", item))
+			}
+			if isRange {
+				if c.stop, err = atoi(last, typ); err != nil || c.stop < c.start {
+					return nil, syntaxError(localeutil.Phrase("invalid value %s", item))
+				}
+			}
+		}
+
+		if !b.valid(c.start) {
+			return nil, syntaxError(localeutil.Phrase("the value %d out of range [%d,%d]", c.start, b.min, b.max))
+		}
+		if c.stop >= 0 && !b.valid(c.stop) {
+			return nil, syntaxError(localeutil.Phrase("the value %d out of range [%d,%d]", c.stop, b.min, b.max))
+		}
+
+		ret = append(ret, c)
+	}
+
+	slices.SortFunc(ret, func(a, b component) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		if a.stop != b.stop {
+			return a.stop - b.stop
+		}
+		return a.repeat - b.repeat
+	})
+	return slices.Compact(ret), nil
+}
+
+// 将 s 转换为数值
+//
+// 两位数的年份，小于 70 的表示 20xx，其它表示 19xx。
+func atoi(s string, typ int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil && typ == yearIndex && len(s) == 2 {
+		if n < 70 {
+			n += 2000
+		} else {
+			n += 1900
+		}
+	}
+	return n, err
+}
+
+func (b bound) valid(v int) bool { return v >= b.min && v <= b.max }
+
+func (c component) match(v int) bool {
+	if v < c.start || (c.stop >= 0 && v > c.stop) {
+		return false
+	}
+	if c.repeat > 0 {
+		return (v-c.start)%c.repeat == 0
+	}
+	return c.stop >= 0 || v == c.start
+}
+
+// 以从后往前的方式匹配 v
+//
+// 仅在重复时与 match 不同，重复的值是从 start 开始递减的。
+func (c component) matchReverse(v int) bool {
+	if c.repeat == 0 || c.stop >= 0 {
+		return c.match(v)
+	}
+	return v <= c.start && (c.start-v)%c.repeat == 0
+}
+
+func (f field) match(v int) bool {
+	return f == nil || slices.ContainsFunc(f, func(c component) bool { return c.match(v) })
+}
+
+func (f field) matchReverse(v int) bool {
+	return f == nil || slices.ContainsFunc(f, func(c component) bool { return c.matchReverse(v) })
+}
+
+// String 返回与 systemd-analyze calendar 中 Normalized form 相同格式的内容
+func (e *Event) String() string {
+	b := &strings.Builder{}
+
+	if e.hasWeekday {
+		e.formatWeekdays(b)
+		b.WriteByte(' ')
+	}
+
+	e.fields[yearIndex].format(b, 4)
+	b.WriteByte('-')
+	e.fields[monthIndex].format(b, 2)
+	if e.endOfMonth {
+		b.WriteByte('~')
+	} else {
+		b.WriteByte('-')
+	}
+	e.fields[dayIndex].format(b, 2)
+	b.WriteByte(' ')
+	e.fields[hourIndex].format(b, 2)
+	b.WriteByte(':')
+	e.fields[minuteIndex].format(b, 2)
+	b.WriteByte(':')
+	e.fields[secondIndex].format(b, 2)
+
+	if e.tz != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.tz)
+	}
+
+	return b.String()
+}
+
+// 连续三个及以上的星期以范围表示，比如 Mon..Wed，其它的以逗号分隔。
+func (e *Event) formatWeekdays(b *strings.Builder) {
+	start := -1
+	comma := false
+
+	flush := func(end int) { // end 为最后一个选中的索引
+		if end > start {
+			if end > start+1 {
+				b.WriteString("..")
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(weekdayNames[end].short)
+		}
+		start = -1
+	}
+
+	for i, w := range weekdayNames {
+		if e.weekdays[w.day] {
+			if start < 0 {
+				if comma {
+					b.WriteByte(',')
+				}
+				comma = true
+				b.WriteString(w.short)
+				start = i
+			}
+		} else if start >= 0 {
+			flush(i - 1)
+		}
+	}
+	if start >= 0 {
+		flush(len(weekdayNames) - 1)
+	}
+}
+
+func (f field) format(b *strings.Builder, width int) {
+	if f == nil {
+		b.WriteByte('*')
+		return
+	}
+
+	for i, c := range f {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, "%0*d", width, c.start)
+		if c.stop >= 0 {
+			fmt.Fprintf(b, "..%0*d", width, c.stop)
+		}
+		if c.repeat > 0 {
+			b.WriteByte('/')
+			b.WriteString(strconv.Itoa(c.repeat))
+		}
+	}
+}
+
+// Location 返回计算时间所采用的时区
+func (e *Event) Location() *time.Location { return e.loc }
+
+func syntaxError(s localeutil.Stringer) error {
+	return localeutil.Error("calendar syntax error %s", s)
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package calendar
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var _ schedulers.Scheduler = &Event{}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 大部分来自 systemd.time 中的示例
+	data := map[string]string{
+		"Sat,Thu,Mon..Wed,Sat..Sun":   "Mon..Thu,Sat,Sun *-*-* 00:00:00",
+		"Mon,Sun 12-*-* 2,1:23":       "Mon,Sun 2012-*-* 01,02:23:00",
+		"Wed *-1":                     "Wed *-*-01 00:00:00",
+		"Wed..Wed,Wed *-1":            "Wed *-*-01 00:00:00",
+		"Wed, 17:48":                  "Wed *-*-* 17:48:00",
+		"Wed..Sat,Tue 12-10-15 1:2:3": "Tue..Sat 2012-10-15 01:02:03",
+		"*-*-7 0:0:0":                 "*-*-07 00:00:00",
+		"10-15":                       "*-10-15 00:00:00",
+		"monday *-12-* 17:00":         "Mon *-12-* 17:00:00",
+		"Mon,Fri *-*-3,1,2 *:30:45":   "Mon,Fri *-*-01,02,03 *:30:45",
+		"12,14,13,12:20,10,30":        "*-*-* 12,13,14:10,20,30:00",
+		"12..14:10,20,30":             "*-*-* 12..14:10,20,30:00",
+		"mon,fri *-1/2-1,3 *:30:45":   "Mon,Fri *-01/2-01,03 *:30:45",
+		"03-05 08:05:40":              "*-03-05 08:05:40",
+		"08:05:40":                    "*-*-* 08:05:40",
+		"05:40":                       "*-*-* 05:40:00",
+		"Sat,Sun 12-05 08:05:40":      "Sat,Sun *-12-05 08:05:40",
+		"Sat,Sun 08:05:40":            "Sat,Sun *-*-* 08:05:40",
+		"2003-03-05 05:40":            "2003-03-05 05:40:00",
+		"2003-02..04-05":              "2003-02..04-05 00:00:00",
+		"2003-03-05 05:40 UTC":        "2003-03-05 05:40:00 UTC",
+		"2003-03-05":                  "2003-03-05 00:00:00",
+		"03-05":                       "*-03-05 00:00:00",
+		"minutely":                    "*-*-* *:*:00",
+		"hourly":                      "*-*-* *:00:00",
+		"daily":                       "*-*-* 00:00:00",
+		"daily UTC":                   "*-*-* 00:00:00 UTC",
+		"monthly":                     "*-*-01 00:00:00",
+		"weekly":                      "Mon *-*-* 00:00:00",
+		"weekly Pacific/Auckland":     "Mon *-*-* 00:00:00 Pacific/Auckland",
+		"yearly":                      "*-01-01 00:00:00",
+		"annually":                    "*-01-01 00:00:00",
+		"quarterly":                   "*-01,04,07,10-01 00:00:00",
+		"semiannually":                "*-01,07-01 00:00:00",
+		"*:2/3":                       "*-*-* *:02/3:00",
+		"*:0/15":                      "*-*-* *:00/15:00",
+		"*:*/15":                      "*-*-* *:00/15:00",
+		"Mon..Fri *-*-* 09:00:00":     "Mon..Fri *-*-* 09:00:00",
+		"Mon-Wed 09:00":               "Mon..Wed *-*-* 09:00:00",
+		"Mon,Tue":                     "Mon,Tue *-*-* 00:00:00",
+		"*-*-01 00:00:00":             "*-*-01 00:00:00",
+		"*-02~03":                     "*-02~03 00:00:00",
+		"Mon *-05~07/1":               "Mon *-05~07/1 00:00:00",
+		"*-*-1..20/5 8..18/2:00":      "*-*-01..20/5 08..18/2:00:00",
+	}
+
+	for spec, normalized := range data {
+		e, err := Parse(spec, time.UTC)
+		a.NotError(err, spec).NotNil(e, spec).
+			Equal(e.String(), normalized, "%s: %s != %s", spec, e.String(), normalized)
+
+		// 规范化之后的内容，可以再次解析
+		e2, err := Parse(e.String(), time.UTC)
+		a.NotError(err, spec).Equal(e2.String(), normalized)
+	}
+
+	e, err := Parse("daily Asia/Shanghai", time.UTC)
+	a.NotError(err).NotNil(e).Equal(e.Location().String(), "Asia/Shanghai")
+
+	e, err = Parse("daily", nil)
+	a.NotError(err).NotNil(e).Equal(e.Location(), time.Local)
+
+	for _, spec := range []string{
+		"",
+		"Mon..Funday",
+		"Fri..Mon",
+		"xx *-*-01",
+		"*-13-01",
+		"*-*-32",
+		"*-*-0",
+		"1960-01-01",
+		"25:00",
+		"*:60",
+		"*:*:60",
+		"1:2:3:4",
+		"1",
+		"*:a",
+		"*:5..1",
+		"*:1..b",
+		"*:*/0",
+		"*:*/a",
+		"*~01~01",
+		"*-*-01 00:00 Not/Exists",
+		"*-*-01 00:00 UTC UTC",
+	} {
+		e, err := Parse(spec, time.UTC)
+		a.Error(err, spec).Nil(e, spec)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := schedulers.Parse("calendar:Mon..Fri 09:00", time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "calendar:Mon..Fri 09:00").
+		Equal(s.Next(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+
+	s, err = schedulers.Parse("calendar:Foo", time.UTC)
+	a.Error(err).Nil(s)
+}
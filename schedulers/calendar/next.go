@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package calendar
+
+import "time"
+
+func (e *Event) Next(last time.Time) time.Time {
+	t := last.In(e.loc).Truncate(time.Second).Add(time.Second)
+	y, m, d := t.Date()
+	h, mi, s := t.Clock()
+
+	for y <= bounds[yearIndex].max {
+		switch {
+		case y < bounds[yearIndex].min || !e.fields[yearIndex].match(y):
+			y, m, d = y+1, time.January, 1
+			h, mi, s = 0, 0, 0
+		case !e.fields[monthIndex].match(int(m)):
+			if m++; m > time.December {
+				y, m = y+1, time.January
+			}
+			d = 1
+			h, mi, s = 0, 0, 0
+		case d > daysInMonth(y, m):
+			if m++; m > time.December {
+				y, m = y+1, time.January
+			}
+			d = 1
+			h, mi, s = 0, 0, 0
+		case !e.matchDay(y, m, d):
+			d++
+			h, mi, s = 0, 0, 0
+		default:
+			// 夏令时结束的当天，同一时钟时间会出现两次，time.Date 返回的是第一次，
+			// 可能早于 last，此时需要继续查找当天之后的时间，而不是直接跳到下一天。
+			// 只有 last 处于第二次出现的时间段内时才会返回第二次出现的时间，
+			// 所以重复的时间段并不会执行两次。
+			for hh, mm, ss, ok := e.nextClock(h, mi, s); ok; hh, mm, ss, ok = e.nextClock(hh, mm, ss+1) {
+				next := time.Date(y, m, d, hh, mm, ss, 0, e.loc)
+				if next.After(last) {
+					return next
+				}
+				if next = repeated(next); next.After(last) {
+					return next
+				}
+			}
+			d++
+			h, mi, s = 0, 0, 0
+		}
+	}
+
+	return time.Time{}
+}
+
+// 日期是否符合日和星期的要求
+func (e *Event) matchDay(y int, m time.Month, d int) bool {
+	if e.endOfMonth {
+		// ~ 表示从月末开始计算，~01 表示最后一天，重复也是从月末往前推算，
+		// 比如 ~07/1 表示最后 7 天。
+		day := daysInMonth(y, m) - d + 1
+		if !e.fields[dayIndex].matchReverse(day) {
+			return false
+		}
+	} else if !e.fields[dayIndex].match(d) {
+		return false
+	}
+
+	return !e.hasWeekday || e.weekdays[time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday()]
+}
+
+// 查找当天不早于 h:mi:s 的第一个时间
+func (e *Event) nextClock(h, mi, s int) (int, int, int, bool) {
+	for hh := h; hh <= bounds[hourIndex].max; hh++ {
+		if !e.fields[hourIndex].match(hh) {
+			continue
+		}
+
+		m0 := 0
+		if hh == h {
+			m0 = mi
+		}
+		for mm := m0; mm <= bounds[minuteIndex].max; mm++ {
+			if !e.fields[minuteIndex].match(mm) {
+				continue
+			}
+
+			s0 := 0
+			if hh == h && mm == mi {
+				s0 = s
+			}
+			for ss := s0; ss <= bounds[secondIndex].max; ss++ {
+				if e.fields[secondIndex].match(ss) {
+					return hh, mm, ss, true
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// 返回与 t 时钟时间相同的第二次出现的时间，不存在则返回零值。
+//
+// 仅在夏令时结束时，时钟回拨的时间段内才存在。
+func repeated(t time.Time) time.Time {
+	_, before := t.Zone()
+	_, after := t.Add(12 * time.Hour).Zone()
+	if before <= after {
+		return time.Time{}
+	}
+
+	next := t.Add(time.Duration(before-after) * time.Second)
+	if next.Hour() != t.Hour() || next.Minute() != t.Minute() || next.Second() != t.Second() {
+		return time.Time{}
+	}
+	return next
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestEvent_Next(t *testing.T) {
+	a := assert.New(t, false)
+
+	type test struct {
+		spec string
+
+		// 第一个元素表示起始值，
+		// 之后的值均是计算 spec 之后的 next 返回值。
+		times []string
+	}
+
+	data := []*test{
+		{
+			spec:  "Mon..Fri *-*-* 09:00:00",
+			times: []string{"2026-10-16 09:00:00", "2026-10-19 09:00:00", "2026-10-20 09:00:00"},
+		},
+		{
+			spec:  "*-*-01 00:00:00",
+			times: []string{"2026-10-16 09:00:00", "2026-11-01 00:00:00", "2026-12-01 00:00:00", "2027-01-01 00:00:00"},
+		},
+		{
+			spec:  "weekly",
+			times: []string{"2026-10-19 00:00:00", "2026-10-26 00:00:00", "2026-11-02 00:00:00"},
+		},
+		{
+			spec:  "*:0/15",
+			times: []string{"2026-10-19 23:40:10", "2026-10-19 23:45:00", "2026-10-20 00:00:00", "2026-10-20 00:15:00"},
+		},
+		{ // 每月的最后一天
+			spec:  "*-*~01 12:00",
+			times: []string{"2026-01-31 12:00:00", "2026-02-28 12:00:00", "2026-03-31 12:00:00", "2026-04-30 12:00:00"},
+		},
+		{ // 每月的最后一个周一
+			spec:  "Mon *-*~07/1",
+			times: []string{"2026-01-01 00:00:00", "2026-01-26 00:00:00", "2026-02-23 00:00:00", "2026-03-30 00:00:00"},
+		},
+		{
+			spec:  "*-02-29",
+			times: []string{"2025-01-01 00:00:00", "2028-02-29 00:00:00", "2032-02-29 00:00:00"},
+		},
+		{
+			spec:  "quarterly",
+			times: []string{"2026-10-19 00:00:00", "2027-01-01 00:00:00", "2027-04-01 00:00:00"},
+		},
+		{
+			spec:  "2026-*-* 23:59:59",
+			times: []string{"2026-12-30 23:59:59", "2026-12-31 23:59:59", "0001-01-01 00:00:00"},
+		},
+		{
+			spec:  "*-02-30",
+			times: []string{"2026-12-30 23:59:59", "0001-01-01 00:00:00"},
+		},
+	}
+
+	for _, item := range data {
+		e, err := Parse(item.spec, time.UTC)
+		a.NotError(err, item.spec).NotNil(e)
+
+		last, err := time.Parse(time.DateTime, item.times[0])
+		a.NotError(err)
+		for _, v := range item.times[1:] {
+			want, err := time.Parse(time.DateTime, v)
+			a.NotError(err)
+
+			next := e.Next(last)
+			if want.Year() == 1 {
+				a.True(next.IsZero(), item.spec)
+				break
+			}
+			a.Equal(next, want, "%s: %s != %s", item.spec, next, want)
+			last = next
+		}
+	}
+
+	// 时区
+	e, err := Parse("*-*-* 09:00 Asia/Shanghai", time.UTC)
+	a.NotError(err).NotNil(e)
+	next := e.Next(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+	a.Equal(next.UTC(), time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC))
+
+	// 夏令时结束的当天，01:00-02:00 会出现两次。
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+	e, err = Parse("*:0/15", ny)
+	a.NotError(err).NotNil(e)
+	last := time.Date(2026, 11, 1, 1, 30, 0, 0, ny) // 第一次出现的 01:30，EDT
+	_, offset := last.Zone()
+	a.Equal(offset, -4*60*60)
+	want := []string{ // 重复的时间段不会执行两次
+		"2026-11-01T01:45:00-04:00",
+		"2026-11-01T02:00:00-05:00",
+		"2026-11-01T02:15:00-05:00",
+	}
+	for _, w := range want {
+		last = e.Next(last)
+		a.Equal(last.Format(time.RFC3339), w)
+	}
+
+	// 从第二次出现的时间开始计算
+	last = time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC).In(ny) // 01:30 EST
+	a.Equal(e.Next(last).Format(time.RFC3339), "2026-11-01T01:45:00-05:00")
+
+	// 夏令时开始的当天，02:00-03:00 不存在。
+	e, err = Parse("*-*-* 01,03:30", ny)
+	a.NotError(err).NotNil(e)
+	next = e.Next(time.Date(2026, 3, 8, 1, 30, 0, 0, ny))
+	a.Equal(next.Format(time.RFC3339), "2026-03-08T03:30:00-04:00")
+}
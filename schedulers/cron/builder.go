@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Builder 以链式调用的方式构建 cron 表达式
+//
+//	s, err := cron.Every().WeekdayRange(time.Monday, time.Friday).At(9, 30, 0).In(loc).Build()
+//
+// 等同于：
+//
+//	s, err := cron.Parse("0 30 9 * * 1-5", loc)
+//
+// 各字段都有两种指定方式：Second 等方法的参数为多个单独的值，
+// SecondRange 等方法的参数为范围的起止值，包含两端。
+//
+// 在调用过程中发生的第一个错误会被保存，并由 [Builder.Spec] 和 [Builder.Build] 返回。
+type Builder struct {
+	values [indexSize][]int // 为 nil 表示 *
+	loc    *time.Location
+	err    error
+}
+
+// Every 声明 [Builder] 对象
+//
+// 所有的字段默认都为 *。
+func Every() *Builder { return &Builder{} }
+
+// Second 指定秒数，参数为多个单独的值
+func (b *Builder) Second(v ...int) *Builder { return b.set(secondIndex, v...) }
+
+// SecondRange 指定从 from 到 to 的秒数范围
+func (b *Builder) SecondRange(from, to int) *Builder {
+	return b.setRange(secondIndex, from, to, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+}
+
+// Minute 指定分钟，参数为多个单独的值
+func (b *Builder) Minute(v ...int) *Builder { return b.set(minuteIndex, v...) }
+
+// MinuteRange 指定从 from 到 to 的分钟范围
+func (b *Builder) MinuteRange(from, to int) *Builder {
+	return b.setRange(minuteIndex, from, to, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+}
+
+// Hour 指定小时，参数为多个单独的值
+func (b *Builder) Hour(v ...int) *Builder { return b.set(hourIndex, v...) }
+
+// HourRange 指定从 from 到 to 的小时范围
+func (b *Builder) HourRange(from, to int) *Builder {
+	return b.setRange(hourIndex, from, to, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+}
+
+// Day 指定每月中的日期，参数为多个单独的值
+func (b *Builder) Day(v ...int) *Builder { return b.set(dayIndex, v...) }
+
+// DayRange 指定从 from 到 to 的日期范围
+func (b *Builder) DayRange(from, to int) *Builder {
+	return b.setRange(dayIndex, from, to, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+}
+
+// Month 指定月份，参数为多个单独的值
+func (b *Builder) Month(v ...time.Month) *Builder {
+	vals := make([]int, 0, len(v))
+	for _, m := range v {
+		vals = append(vals, int(m))
+	}
+	return b.set(monthIndex, vals...)
+}
+
+// MonthRange 指定从 from 到 to 的月份范围
+//
+// from 不能大于 to，即不能跨越年份。
+func (b *Builder) MonthRange(from, to time.Month) *Builder {
+	return b.setRange(monthIndex, int(from), int(to), from.String()+"-"+to.String())
+}
+
+// Weekday 指定星期，参数为多个单独的值
+func (b *Builder) Weekday(v ...time.Weekday) *Builder {
+	vals := make([]int, 0, len(v))
+	for _, w := range v {
+		vals = append(vals, int(w))
+	}
+	return b.set(weekIndex, vals...)
+}
+
+// WeekdayRange 指定从 from 到 to 的星期范围
+//
+// from 不能大于 to，即不能跨越周日。
+func (b *Builder) WeekdayRange(from, to time.Weekday) *Builder {
+	return b.setRange(weekIndex, int(from), int(to), from.String()+"-"+to.String())
+}
+
+// At 指定时间
+func (b *Builder) At(hour, minute, second int) *Builder {
+	return b.Hour(hour).Minute(minute).Second(second)
+}
+
+// In 指定时区
+//
+// 如果未指定，则采用 [time.Local]。
+func (b *Builder) In(loc *time.Location) *Builder {
+	b.loc = loc
+	return b
+}
+
+// 指定从 from 到 to 的范围，text 为出错时范围的文本表示。
+func (b *Builder) setRange(index, from, to int, text string) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if from > to {
+		b.err = syntaxError(localeutil.Phrase("invalid value %s", text))
+		return b
+	}
+
+	vals := make([]int, 0, to-from+1)
+	for v := from; v <= to; v++ {
+		vals = append(vals, v)
+	}
+	return b.set(index, vals...)
+}
+
+func (b *Builder) set(index int, v ...int) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(v) == 0 {
+		b.err = syntaxError(localeutil.Phrase("can not be empty"))
+		return b
+	}
+
+	bd := bounds[index]
+	if index == weekIndex {
+		bd.max = 6 // time.Weekday 不存在 7
+	}
+	for i, val := range v {
+		if !bd.valid(val) {
+			b.err = syntaxError(localeutil.Phrase("the value %d out of range [%d,%d]", val, bd.min, bd.max))
+			return b
+		}
+		if slices.Contains(v[:i], val) {
+			b.err = syntaxError(localeutil.Phrase("duplicate value %d", val))
+			return b
+		}
+	}
+
+	vals := slices.Clone(v)
+	slices.Sort(vals)
+	b.values[index] = vals
+	return b
+}
+
+// Spec 返回与 [Parse] 等价的 cron 表达式
+func (b *Builder) Spec() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+
+	if !slices.ContainsFunc(b.values[:], func(vals []int) bool { return vals != nil }) {
+		return "", syntaxError(localeutil.Phrase("all items are asterisk"))
+	}
+
+	fields := make([]string, 0, indexSize)
+	for _, vals := range b.values {
+		fields = append(fields, formatField(vals))
+	}
+	spec := strings.Join(fields, " ")
+
+	if !b.possible() {
+		return "", syntaxError(localeutil.Phrase("never matches %s", spec))
+	}
+	return spec, nil
+}
+
+// Build 生成 [schedulers.Scheduler] 对象
+//
+// 与将 [Builder.Spec] 的返回值传递给 [Parse] 的结果相同。
+func (b *Builder) Build() (schedulers.Scheduler, error) {
+	spec, err := b.Spec()
+	if err != nil {
+		return nil, err
+	}
+	loc := b.loc
+	if loc == nil {
+		loc = time.Local
+	}
+	return Parse(spec, loc)
+}
+
+// 指定的日期是否至少在一个指定的月份中存在
+func (b *Builder) possible() bool {
+	days, months := b.values[dayIndex], b.values[monthIndex]
+	if days == nil || months == nil {
+		return true
+	}
+
+	for _, m := range months {
+		if days[0] <= getMonthDays(time.Month(m), 2000) { // 2000 为闰年
+			return true
+		}
+	}
+	return false
+}
+
+// 将已排序的 vals 转换为 cron 字段，连续三个及以上的值会合并为范围。
+func formatField(vals []int) string {
+	if vals == nil {
+		return "*"
+	}
+
+	items := make([]string, 0, len(vals))
+	for i := 0; i < len(vals); {
+		j := i
+		for j+1 < len(vals) && vals[j+1] == vals[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 {
+			items = append(items, strconv.Itoa(vals[i])+"-"+strconv.Itoa(vals[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, strconv.Itoa(vals[k]))
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+	"github.com/issue9/localeutil"
+)
+
+func TestBuilder(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	data := []*struct {
+		b    *Builder
+		spec string
+	}{
+		{b: Every().WeekdayRange(time.Monday, time.Friday).At(9, 30, 0), spec: "0 30 9 * * 1-5"},
+		{b: Every().Weekday(time.Monday, time.Friday).At(9, 30, 0), spec: "0 30 9 * * 1,5"},
+		{b: Every().Weekday(time.Sunday, time.Saturday, time.Monday, time.Tuesday), spec: "* * * * * 0-2,6"},
+		{b: Every().Second(0), spec: "0 * * * * *"},
+		{b: Every().Second(0).Minute(0, 15, 30, 45), spec: "0 0,15,30,45 * * * *"},
+		{b: Every().Day(1, 15).Month(time.January, time.July).At(0, 0, 0), spec: "0 0 0 1,15 1,7 *"},
+		{b: Every().Hour(9, 10, 11, 12, 14).Minute(0).Second(0), spec: "0 0 9-12,14 * * *"},
+		{b: Every().Day(31).Month(time.January, time.February).At(0, 0, 0), spec: "0 0 0 31 1,2 *"},
+		{b: Every().Day(29).Month(time.February).At(0, 0, 0), spec: "0 0 0 29 2 *"},
+		{b: Every().SecondRange(0, 2).MinuteRange(10, 10), spec: "0-2 10 * * * *"},
+		{b: Every().HourRange(9, 17).Minute(0).Second(0), spec: "0 0 9-17 * * *"},
+		{b: Every().DayRange(1, 7).MonthRange(time.March, time.May).At(0, 0, 0), spec: "0 0 0 1-7 3-5 *"},
+	}
+
+	for _, item := range data {
+		spec, err := item.b.Spec()
+		a.NotError(err).Equal(spec, item.spec)
+
+		s, err := item.b.In(loc).Build()
+		a.NotError(err).NotNil(s)
+		expected, err := Parse(item.spec, loc)
+		a.NotError(err).NotNil(expected)
+		a.Equal(s.(*cron).data, expected.(*cron).data, item.spec).
+			Equal(s.(*cron).loc, loc)
+	}
+
+	// 默认时区
+	s, err := Every().Second(0).Build()
+	a.NotError(err).NotNil(s).Equal(s.(*cron).loc, time.Local)
+
+	// 错误
+	for _, b := range []*Builder{
+		Every(),                              // 全部为 *
+		Every().Second(60),                   // 超出范围
+		Every().Weekday(7),                   // 超出范围
+		Every().Hour(1, 2, 1),                // 重复
+		Every().Minute(),                     // 空值
+		Every().WeekdayRange(time.Friday, 1), // 范围错误
+		Every().MonthRange(time.May, 1),      // 范围错误
+		Every().HourRange(20, 24),            // 超出范围
+		Every().Day(30, 31).Month(time.February).At(0, 0, 0), // 不存在的日期
+		Every().Second(-1).Minute(5),                         // 保留第一个错误
+		Every().Second(-1).HourRange(5, 1),                   // 出错之后的范围错误
+	} {
+		spec, err := b.Spec()
+		a.Error(err).Empty(spec)
+
+		s, err := b.Build()
+		a.Error(err).Nil(s)
+	}
+
+	// 返回的是第一个错误
+	_, err = Every().Second(-1).HourRange(5, 1).Spec()
+	a.Equal(err.Error(), syntaxError(localeutil.Phrase("the value %d out of range [%d,%d]", -1, 0, 59)).Error())
+}
+
+func TestFormatField(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(formatField(nil), "*").
+		Equal(formatField([]int{1}), "1").
+		Equal(formatField([]int{1, 2}), "1,2").
+		Equal(formatField([]int{1, 2, 3}), "1-3").
+		Equal(formatField([]int{0, 2, 3, 4, 6, 7}), "0,2-4,6,7")
+}
@@ -16,6 +16,10 @@ import (
 	"github.com/issue9/scheduled/schedulers/at"
 )
 
+func init() {
+	schedulers.Register("cron", Parse)
+}
+
 // 表示 cron.data 中各个元素的索引值
 const (
 	secondIndex = iota
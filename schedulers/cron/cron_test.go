@@ -121,3 +121,15 @@ func TestParse(t *testing.T) {
 		a.Equal(c.data, v.vals, "测试 %s 时出错，期望值：%v，实际返回值：%v", v.expr, v.vals, c.data)
 	}
 }
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := schedulers.Parse("cron:0 0 9 * * 1-5", time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "cron:0 0 9 * * 1-5").
+		Equal(s.Next(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+
+	s, err = schedulers.Parse("cron:0 0 9", time.UTC)
+	a.Error(err).Nil(s)
+}
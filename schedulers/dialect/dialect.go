@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package dialect 提供了各类 cron 方言以及 systemd 定时器之间的相互转换
+//
+// 目前支持以下几种格式：
+//   - [Scheduled] 即 [cron] 包实现的带秒数的 6 位 cron 表达式；
+//   - [Vixie] 传统 crontab 中的 5 位表达式；
+//   - [Quartz] Quartz 中带秒数以及可选年份的表达式，支持 L、W 和 # 等字符；
+//   - [Systemd] systemd 中的 OnCalendar 表达式，即 [calendar] 包实现的内容。
+//
+// 各格式的表达能力并不相同，无法表示的内容会以 [ConvertError] 的形式返回。
+//
+// [cron]: https://pkg.go.dev/github.com/issue9/scheduled/schedulers/cron
+// [calendar]: https://pkg.go.dev/github.com/issue9/scheduled/schedulers/calendar
+package dialect
+
+import (
+	"strings"
+
+	"github.com/issue9/localeutil"
+)
+
+// 支持的格式
+const (
+	Scheduled Dialect = iota
+	Vixie
+	Quartz
+	Systemd
+)
+
+// 表示 Expr.fields 中各个元素的索引值
+const (
+	secondField = iota
+	minuteField
+	hourField
+	dayField
+	monthField
+	weekdayField
+	yearField
+	fieldSize
+)
+
+var dialects = []string{"scheduled", "vixie", "quartz", "systemd"}
+
+var fieldNames = []string{"second", "minute", "hour", "day-of-month", "month", "day-of-week", "year"}
+
+// 各字段在 Expr 中的取值范围，星期以 [time.Weekday] 表示。
+var bounds = []bound{
+	{min: 0, max: 59},      // secondField
+	{min: 0, max: 59},      // minuteField
+	{min: 0, max: 23},      // hourField
+	{min: 1, max: 31},      // dayField
+	{min: 1, max: 12},      // monthField
+	{min: 0, max: 6},       // weekdayField
+	{min: 1970, max: 2199}, // yearField
+}
+
+type (
+	// Dialect 表示表达式的格式
+	Dialect int8
+
+	bound struct{ min, max int }
+
+	// 字段中所有有效的值，从小到大排列，nil 表示任意值。
+	values []int
+
+	// Expr 与具体格式无关的表达式
+	//
+	// 由 [Parse] 从某一格式中解析得到，再由 [Expr.Format] 输出为其它的格式。
+	Expr struct {
+		fields [fieldSize]values
+		raw    [fieldSize]string // 各字段在原始表达式中的内容，用于错误信息。
+
+		dayFromEnd bool // 日期从月末开始计算，1 表示最后一天；
+		nearest    bool // 日期取离其最近的工作日，对应 Quartz 中的 W；
+		nth        int  // 星期在当月中第几次出现，-1 表示最后一次，0 表示不限制；
+		dayOr      bool // 日期与星期都有限制时，是否以或的方式组合；
+		reboot     bool // @reboot
+		tz         string
+	}
+
+	// ConvertError 表达式中包含目标格式无法表示的内容
+	ConvertError struct {
+		Dialect    Dialect     // 目标格式
+		Constructs []Construct // 所有无法转换的内容
+	}
+
+	// Construct 表达式中的某一组成部分
+	Construct struct {
+		Field string // 字段名称，比如 second、day-of-week 等
+		Value string // 该字段在原始表达式中的内容
+	}
+)
+
+// ParseDialect 将名称转换为 [Dialect]
+//
+// name 不区分大小写，可以是 scheduled、vixie、quartz 和 systemd。
+func ParseDialect(name string) (Dialect, error) {
+	for i, n := range dialects {
+		if strings.EqualFold(n, name) {
+			return Dialect(i), nil
+		}
+	}
+	return 0, localeutil.Error("invalid dialect %s", name)
+}
+
+func (d Dialect) String() string {
+	if d < Scheduled || d > Systemd {
+		return "<unknown>"
+	}
+	return dialects[d]
+}
+
+// Convert 将 from 格式的 spec 转换为 to 格式
+//
+// 如果 spec 中包含 to 无法表示的内容，返回 [ConvertError]。
+func Convert(spec string, from, to Dialect) (string, error) {
+	e, err := Parse(spec, from)
+	if err != nil {
+		return "", err
+	}
+	return e.Format(to)
+}
+
+func (c Construct) String() string { return c.Field + " " + c.Value }
+
+func (err *ConvertError) Error() string { return err.LocaleString(nil) }
+
+func (err *ConvertError) LocaleString(p *localeutil.Printer) string {
+	list := make([]string, 0, len(err.Constructs))
+	for _, c := range err.Constructs {
+		list = append(list, c.String())
+	}
+	return localeutil.Phrase("can not convert %s to %s", strings.Join(list, ", "), err.Dialect.String()).LocaleString(p)
+}
+
+func (b bound) valid(v int) bool { return v >= b.min && v <= b.max }
+
+func (b bound) full(vals values) bool { return len(vals) == b.max-b.min+1 }
+
+// 日期与星期的组合是否需要同时满足
+func (e *Expr) dayAnd() bool {
+	return !e.dayOr && e.restricted(dayField) && e.restricted(weekdayField)
+}
+
+// 字段是否有限制
+func (e *Expr) restricted(f int) bool {
+	switch f {
+	case dayField:
+		return e.fields[dayField] != nil || e.dayFromEnd || e.nearest
+	case weekdayField:
+		return e.fields[weekdayField] != nil || e.nth != 0
+	default:
+		return e.fields[f] != nil
+	}
+}
+
+// 日期是否表示星期在当月中第几次出现
+//
+// 即日期为 1..7、8..14、15..21、22..28、29..31 或是月末的 7 天，
+// 返回值与 nth 字段的含义相同。
+func (e *Expr) weekOfMonth() (int, bool) {
+	vals := e.fields[dayField]
+	if e.nearest || len(e.fields[weekdayField]) != 1 || len(vals) == 0 || vals[len(vals)-1]-vals[0] != len(vals)-1 {
+		return 0, false
+	}
+
+	switch first := vals[0]; {
+	case e.dayFromEnd:
+		if first == 1 && len(vals) == 7 {
+			return -1, true
+		}
+	case first == 29 && len(vals) == 3:
+		return 5, true
+	case (first-1)%7 == 0 && len(vals) == 7:
+		return (first-1)/7 + 1, true
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package dialect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func TestParseDialect(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, d := range []Dialect{Scheduled, Vixie, Quartz, Systemd} {
+		v, err := ParseDialect(d.String())
+		a.NotError(err).Equal(v, d)
+	}
+
+	d, err := ParseDialect("Quartz")
+	a.NotError(err).Equal(d, Quartz)
+
+	_, err = ParseDialect("fcron")
+	a.Equal(err.Error(), "invalid dialect fcron")
+
+	a.Equal(Dialect(-1).String(), "<unknown>").
+		Equal(Dialect(10).String(), "<unknown>")
+}
+
+func TestConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := Convert("0 0/15 9-17 ? * MON-FRI", Quartz, Vixie)
+	a.NotError(err).Equal(s, "*/15 9-17 * * 1-5")
+
+	s, err = Convert("@weekly", Vixie, Systemd)
+	a.NotError(err).Equal(s, "Sun *-*-* 00:00:00")
+
+	// 语法错误
+	s, err = Convert("0 0 12 * * *", Quartz, Vixie)
+	a.Empty(s)
+	var ie *schedulers.InvalidError
+	a.True(errors.As(err, &ie)).Equal(ie.Spec, "0 0 12 * * *")
+
+	// 无法转换
+	s, err = Convert("0 30 12 15W * ? 2030", Quartz, Vixie)
+	a.Empty(s)
+	var ce *ConvertError
+	a.True(errors.As(err, &ce)).
+		Equal(ce.Dialect, Vixie).
+		Equal(ce.Constructs, []Construct{{Field: "day-of-month", Value: "15W"}, {Field: "year", Value: "2030"}}).
+		Equal(err.Error(), "can not convert day-of-month 15W, year 2030 to vixie")
+}
@@ -0,0 +1,450 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package dialect
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/localeutil"
+)
+
+// 输出字段内容时采用的格式
+type style struct {
+	bound               // 输出的取值范围
+	sep   string        // 范围的分隔符
+	step  bool          // 是否可以使用 n1-n2/step
+	open  bool          // 是否可以使用 n/step
+	star  bool          // 是否可以使用 */step
+	width int           // 数值的最小宽度
+	names []string      // 不为空时以名称代替数值，names[i] 表示值 min+i。
+	conv  func(int) int // 将 Expr 中的值转换为输出的值，为空表示不需要转换。
+}
+
+var (
+	cronStyle      = style{sep: "-", step: true, star: true}
+	scheduledStyle = style{sep: "-"}
+	quartzStyle    = style{sep: "-", step: true, open: true}
+	systemdStyle   = style{sep: "..", step: true, open: true, width: 2}
+
+	// Quartz 中的星期以 1 表示周日
+	quartzWeekdayStyle = style{
+		bound: bound{min: 1, max: 7},
+		sep:   "-",
+		conv:  func(v int) int { return v + 1 },
+	}
+
+	systemdWeekdayStyle = style{
+		bound: bound{min: 0, max: 6},
+		sep:   "..",
+		names: []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+		conv:  func(v int) int { return (v + 6) % 7 },
+	}
+)
+
+// 收集无法转换的内容
+type formatter struct {
+	e          *Expr
+	constructs []Construct
+}
+
+// Format 将表达式输出为 d 格式
+//
+// 如果表达式中包含 d 无法表示的内容，返回 [ConvertError]。
+func (e *Expr) Format(d Dialect) (string, error) {
+	f := &formatter{e: e}
+
+	var s string
+	switch d {
+	case Scheduled:
+		s = f.scheduled()
+	case Vixie:
+		s = f.vixie()
+	case Quartz:
+		s = f.quartz()
+	case Systemd:
+		s = f.systemd()
+	default:
+		return "", localeutil.Error("invalid dialect %s", d.String())
+	}
+
+	if len(f.constructs) > 0 {
+		return "", &ConvertError{Dialect: d, Constructs: f.constructs}
+	}
+	return s, nil
+}
+
+func (f *formatter) unsupported(field int) {
+	f.constructs = append(f.constructs, Construct{Field: fieldNames[field], Value: f.e.raw[field]})
+}
+
+// 检测 cron 类表达式都无法表示的日期和星期：从月末计算的日期、W、L 和 # 等。
+func (f *formatter) checkCron() {
+	if f.e.dayFromEnd || f.e.nearest {
+		f.unsupported(dayField)
+	}
+	if f.e.nth != 0 {
+		f.unsupported(weekdayField)
+	}
+}
+
+// 年份不能大于 max
+func (f *formatter) checkYear(max int) {
+	if vals := f.e.fields[yearField]; vals != nil && vals[len(vals)-1] > max {
+		f.unsupported(yearField)
+	}
+}
+
+func (f *formatter) checkTimezone() {
+	if f.e.tz != "" {
+		f.constructs = append(f.constructs, Construct{Field: "timezone", Value: f.e.tz})
+	}
+}
+
+func (f *formatter) checkReboot() {
+	if f.e.reboot {
+		f.constructs = append(f.constructs, Construct{Field: "nickname", Value: "@reboot"})
+	}
+}
+
+// 日期与星期的组合方式无法表示
+func (f *formatter) unsupportedCombination() {
+	name := fieldNames[dayField] + " and " + fieldNames[weekdayField]
+	if f.e.dayOr {
+		name = fieldNames[dayField] + " or " + fieldNames[weekdayField]
+	}
+	f.constructs = append(f.constructs, Construct{Field: name, Value: f.e.raw[dayField] + " " + f.e.raw[weekdayField]})
+}
+
+func (f *formatter) scheduled() string {
+	e := f.e
+	if e.reboot {
+		return "@reboot"
+	}
+
+	f.checkCron()
+	if e.dayAnd() {
+		f.unsupportedCombination()
+	}
+	f.checkYear(0)
+	f.checkTimezone()
+
+	fs := make([]string, 0, weekdayField+1)
+	for i := secondField; i <= weekdayField; i++ {
+		fs = append(fs, scheduledStyle.field(i).format(e.fields[i]))
+	}
+
+	// 所有项都为 * 会被 cron.Parse 拒绝
+	if !slices.ContainsFunc(fs, func(s string) bool { return s != "*" }) {
+		fs[secondField] = scheduledStyle.field(secondField).formatValues(allValues(bounds[secondField]))
+	}
+
+	return strings.Join(fs, " ")
+}
+
+func (f *formatter) vixie() string {
+	e := f.e
+	if e.reboot {
+		return "@reboot"
+	}
+
+	if !slices.Equal(e.fields[secondField], values{0}) {
+		f.unsupported(secondField)
+	}
+	f.checkCron()
+
+	day := cronStyle.field(dayField)
+	week := cronStyle.field(weekdayField)
+	week.step = false
+
+	var dayText, weekText string
+	switch {
+	case e.dayOr: // 以 * 开头会被 vixie 当作与的方式组合
+		day.star = false
+	case e.dayAnd(): // 至少需要一个字段以 * 开头
+		if s, ok := starStep(e.fields[dayField], day); ok {
+			dayText = s
+		} else if s, ok := starStep(e.fields[weekdayField], week); ok {
+			weekText = s
+		} else {
+			f.unsupportedCombination()
+		}
+	}
+
+	if dayText == "" {
+		dayText = day.format(e.fields[dayField])
+	}
+	if weekText == "" {
+		weekText = week.format(e.fields[weekdayField])
+	}
+
+	f.checkYear(0)
+	f.checkTimezone()
+
+	return strings.Join([]string{
+		cronStyle.field(minuteField).format(e.fields[minuteField]),
+		cronStyle.field(hourField).format(e.fields[hourField]),
+		dayText,
+		cronStyle.field(monthField).format(e.fields[monthField]),
+		weekText,
+	}, " ")
+}
+
+func (f *formatter) quartz() string {
+	e := f.e
+	f.checkReboot()
+
+	fs := make([]string, 0, fieldSize)
+	for i := secondField; i <= hourField; i++ {
+		fs = append(fs, quartzStyle.field(i).format(e.fields[i]))
+	}
+
+	dayRestricted := e.restricted(dayField)
+	weekRestricted := e.restricted(weekdayField)
+	nth := e.nth
+	if dayRestricted && weekRestricted && nth == 0 && !e.dayOr { // 比如 systemd 中的 Fri *-*-15..21
+		if n, ok := e.weekOfMonth(); ok {
+			nth, dayRestricted = n, false
+		}
+	}
+
+	day := "?"
+	vals := e.fields[dayField]
+	switch {
+	case weekRestricted && !dayRestricted:
+	case e.dayFromEnd:
+		switch {
+		case len(vals) != 1 || (e.nearest && vals[0] != 1):
+			f.unsupported(dayField)
+		case e.nearest:
+			day = "LW"
+		case vals[0] == 1:
+			day = "L"
+		default:
+			day = "L-" + strconv.Itoa(vals[0]-1)
+		}
+	case e.nearest:
+		if len(vals) != 1 {
+			f.unsupported(dayField)
+		} else {
+			day = strconv.Itoa(vals[0]) + "W"
+		}
+	default:
+		day = quartzStyle.field(dayField).format(vals)
+	}
+	fs = append(fs, day, quartzStyle.field(monthField).format(e.fields[monthField]))
+
+	week := "?"
+	vals = e.fields[weekdayField]
+	switch {
+	case dayRestricted || !weekRestricted:
+	case nth != 0:
+		if len(vals) != 1 {
+			f.unsupported(weekdayField)
+		} else if w := strconv.Itoa(vals[0] + 1); nth < 0 {
+			week = w + "L"
+		} else {
+			week = w + "#" + strconv.Itoa(nth)
+		}
+	default:
+		week = quartzWeekdayStyle.format(vals)
+	}
+	fs = append(fs, week)
+
+	if dayRestricted && weekRestricted {
+		f.unsupportedCombination()
+	}
+
+	f.checkYear(2099)
+	if e.fields[yearField] != nil {
+		fs = append(fs, quartzStyle.field(yearField).format(e.fields[yearField]))
+	}
+	f.checkTimezone()
+
+	return strings.Join(fs, " ")
+}
+
+func (f *formatter) systemd() string {
+	e := f.e
+	f.checkReboot()
+	if e.nearest {
+		f.unsupported(dayField)
+	}
+	if e.dayOr {
+		f.unsupportedCombination()
+	}
+
+	// 以日期的范围表示星期在当月中出现的次数，比如 Fri *-*-15..21 表示第三个周五。
+	nthDay := ""
+	switch {
+	case e.nth == 0:
+	case e.restricted(dayField) || len(e.fields[weekdayField]) != 1:
+		f.unsupported(weekdayField)
+	case e.nth < 0:
+		nthDay = "~07/1"
+	default:
+		first := (e.nth-1)*7 + 1
+		nthDay = fmt.Sprintf("-%02d..%02d", first, min(first+6, bounds[dayField].max))
+	}
+
+	b := &strings.Builder{}
+	if vals := e.fields[weekdayField]; vals != nil {
+		b.WriteString(systemdWeekdayStyle.format(vals))
+		b.WriteByte(' ')
+	}
+
+	year := systemdStyle.field(yearField)
+	year.width = 4
+	b.WriteString(year.format(e.fields[yearField]))
+	b.WriteByte('-')
+	b.WriteString(systemdStyle.field(monthField).format(e.fields[monthField]))
+
+	day := systemdStyle.field(dayField)
+	switch {
+	case nthDay != "":
+		b.WriteString(nthDay)
+	case e.dayFromEnd:
+		// ~ 之后的 n/step 是从 n 往前推算的，与其它字段的含义不同。
+		b.WriteByte('~')
+		day.open = false
+		b.WriteString(day.format(e.fields[dayField]))
+	default:
+		b.WriteByte('-')
+		b.WriteString(day.format(e.fields[dayField]))
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(systemdStyle.field(hourField).format(e.fields[hourField]))
+	b.WriteByte(':')
+	b.WriteString(systemdStyle.field(minuteField).format(e.fields[minuteField]))
+	b.WriteByte(':')
+	b.WriteString(systemdStyle.field(secondField).format(e.fields[secondField]))
+
+	if e.tz != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.tz)
+	}
+
+	return b.String()
+}
+
+// 返回用于字段 f 的格式
+func (st style) field(f int) style {
+	st.bound = bounds[f]
+	return st
+}
+
+func (st style) format(vals values) string {
+	if vals == nil {
+		return "*"
+	}
+	return st.formatValues(vals)
+}
+
+func (st style) formatValues(vals values) string {
+	if st.conv != nil {
+		list := make(values, 0, len(vals))
+		for _, v := range vals {
+			list = append(list, st.conv(v))
+		}
+		slices.Sort(list)
+		vals = list
+	}
+
+	if st.step {
+		if s, ok := st.formatStep(vals); ok {
+			return s
+		}
+	}
+
+	// 连续三个及以上的值以范围表示，其它的以逗号分隔。
+	b := &strings.Builder{}
+	for i := 0; i < len(vals); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(st.value(vals[i]))
+
+		j := i
+		for j+1 < len(vals) && vals[j+1] == vals[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			b.WriteString(st.sep)
+			b.WriteString(st.value(vals[j]))
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// 如果 vals 是一个等差数列，则以步长的形式输出。
+func (st style) formatStep(vals values) (string, bool) {
+	step, ok := arithmetic(vals)
+	if !ok {
+		return "", false
+	}
+
+	first, last := vals[0], vals[len(vals)-1]
+	toEnd := last+step > st.max
+	switch {
+	case toEnd && first == st.min && st.star:
+		return "*/" + strconv.Itoa(step), true
+	case len(vals) < 3:
+		return "", false
+	case toEnd && st.open:
+		return st.value(first) + "/" + strconv.Itoa(step), true
+	default:
+		return st.value(first) + st.sep + st.value(last) + "/" + strconv.Itoa(step), true
+	}
+}
+
+func (st style) value(v int) string {
+	if st.names != nil {
+		return st.names[v-st.min]
+	}
+	return fmt.Sprintf("%0*d", st.width, v)
+}
+
+// 以 */step 的形式输出 vals，vals 需要从最小值开始并一直延续到最大值附近。
+func starStep(vals values, st style) (string, bool) {
+	if vals == nil {
+		return "*", true
+	}
+
+	step, ok := arithmetic(vals)
+	if !ok || vals[0] != st.min || vals[len(vals)-1]+step <= st.max {
+		return "", false
+	}
+	return "*/" + strconv.Itoa(step), true
+}
+
+// 返回等差数列的公差，仅包含一个值或是公差为 1 时返回 false。
+func arithmetic(vals values) (int, bool) {
+	if len(vals) < 2 {
+		return 0, false
+	}
+
+	step := vals[1] - vals[0]
+	if step < 2 {
+		return 0, false
+	}
+	for i := 2; i < len(vals); i++ {
+		if vals[i]-vals[i-1] != step {
+			return 0, false
+		}
+	}
+	return step, true
+}
+
+func allValues(b bound) values {
+	vals := make(values, 0, b.max-b.min+1)
+	for v := b.min; v <= b.max; v++ {
+		vals = append(vals, v)
+	}
+	return vals
+}
@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package dialect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/calendar"
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+func TestStyle_format(t *testing.T) {
+	a := assert.New(t, false)
+
+	minute := cronStyle.field(minuteField)
+	a.Equal(minute.format(nil), "*").
+		Equal(minute.format(values{5}), "5").
+		Equal(minute.format(values{0, 30}), "*/30").
+		Equal(minute.format(values{5, 35}), "5,35").
+		Equal(minute.format(values{5, 20, 35, 50}), "5-50/15").
+		Equal(minute.format(values{1, 2, 3, 5, 6, 8}), "1-3,5,6,8")
+
+	a.Equal(quartzStyle.field(minuteField).format(values{5, 20, 35, 50}), "5/15").
+		Equal(quartzStyle.field(minuteField).format(values{5, 20, 35}), "5-35/15").
+		Equal(scheduledStyle.field(minuteField).format(values{0, 20, 40}), "0,20,40").
+		Equal(systemdStyle.field(hourField).format(values{8, 9, 10}), "08..10").
+		Equal(systemdStyle.field(minuteField).format(values{0, 15, 30, 45}), "00/15")
+
+	a.Equal(quartzWeekdayStyle.format(values{0, 6}), "1,7").
+		Equal(systemdWeekdayStyle.format(values{0, 1, 2, 3}), "Mon..Wed,Sun").
+		Equal(systemdWeekdayStyle.format(values{0, 6}), "Sat,Sun")
+
+	s, ok := starStep(values{1, 3, 5, 7, 9, 11, 13, 15, 17, 19, 21, 23, 25, 27, 29, 31}, cronStyle.field(dayField))
+	a.True(ok).Equal(s, "*/2")
+	_, ok = starStep(values{1, 3, 5}, cronStyle.field(dayField))
+	a.False(ok)
+}
+
+func TestExpr_Format(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		spec     string
+		from, to Dialect
+		want     string
+	}{
+		{spec: "0 0/15 9-17 ? * MON-FRI", from: Quartz, to: Scheduled, want: "0 0,15,30,45 9-17 * * 1-5"},
+		{spec: "0 0/15 9-17 ? * MON-FRI", from: Quartz, to: Systemd, want: "Mon..Fri *-*-* 09..17:00/15:00"},
+		{spec: "0 0 12 L * ?", from: Quartz, to: Systemd, want: "*-*~01 12:00:00"},
+		{spec: "0 0 12 ? * 6#3", from: Quartz, to: Systemd, want: "Fri *-*-15..21 12:00:00"},
+		{spec: "0 0 12 ? * 2#5", from: Quartz, to: Systemd, want: "Mon *-*-29..31 12:00:00"},
+		{spec: "0 0 12 ? * 6L", from: Quartz, to: Systemd, want: "Fri *-*~07/1 12:00:00"},
+		{spec: "Mon *-*~07/1", from: Systemd, to: Quartz, want: "0 0 0 ? * 2L"},
+		{spec: "Sun *-*-01..07 09:00", from: Systemd, to: Quartz, want: "0 0 9 ? * 1#1"},
+		{spec: "0 0 12 ? * 6#3", from: Quartz, to: Quartz, want: "0 0 12 ? * 6#3"},
+		{spec: "0 0 12 ? * FRIL", from: Quartz, to: Quartz, want: "0 0 12 ? * 6L"},
+		{spec: "0 0 12 L-2 * ?", from: Quartz, to: Quartz, want: "0 0 12 L-2 * ?"},
+		{spec: "0 0 12 LW * ? 2030", from: Quartz, to: Quartz, want: "0 0 12 LW * ? 2030"},
+		{spec: "0 0 12 15W * ?", from: Quartz, to: Quartz, want: "0 0 12 15W * ?"},
+		{spec: "* * * * * ?", from: Quartz, to: Scheduled, want: "0-59 * * * * *"},
+		{spec: "* * * * * ?", from: Quartz, to: Systemd, want: "*-*-* *:*:*"},
+		{spec: "*/5 * * * *", from: Vixie, to: Quartz, want: "0 0/5 * * * ?"},
+		{spec: "0 9 1,15 * 1", from: Vixie, to: Scheduled, want: "0 0 9 1,15 * 1"},
+		{spec: "0 9 */2 * 1", from: Vixie, to: Systemd, want: "Mon *-*-01/2 09:00:00"},
+		{spec: "0 9 */2 * 1", from: Vixie, to: Vixie, want: "0 9 */2 * 1"},
+		{spec: "0 9 1-31/2 * 1", from: Vixie, to: Vixie, want: "0 9 1-31/2 * 1"}, // 或的方式组合，不能以 * 开头。
+		{spec: "@reboot", from: Vixie, to: Scheduled, want: "@reboot"},
+		{spec: "@weekly", from: Vixie, to: Quartz, want: "0 0 0 ? * 1"},
+		{spec: "0 30 9 * * 1-5", from: Scheduled, to: Vixie, want: "30 9 * * 1-5"},
+		{spec: "0 30 9 * * 1-5", from: Scheduled, to: Quartz, want: "0 30 9 ? * 2-6"},
+		{spec: "@reboot", from: Scheduled, to: Vixie, want: "@reboot"},
+		{spec: "Mon..Fri *-*-* 09:00:00", from: Systemd, to: Vixie, want: "0 9 * * 1-5"},
+		{spec: "Sat,Sun *-*-* 10:00", from: Systemd, to: Vixie, want: "0 10 * * 0,6"},
+		{spec: "*-*~1..3 00:00:00", from: Systemd, to: Systemd, want: "*-*~01..03 00:00:00"},
+		{spec: "*-*~03 00:00:00", from: Systemd, to: Quartz, want: "0 0 0 L-2 * ?"},
+		{spec: "quarterly", from: Systemd, to: Vixie, want: "0 0 1 */3 *"},
+		{spec: "2030-01-01 00:00:00", from: Systemd, to: Quartz, want: "0 0 0 1 1 ? 2030"},
+	}
+	for _, item := range data {
+		e, err := Parse(item.spec, item.from)
+		a.NotError(err, item.spec).NotNil(e, item.spec)
+		s, err := e.Format(item.to)
+		a.NotError(err, item.spec).Equal(s, item.want, item.spec)
+	}
+
+	errs := []*struct {
+		spec     string
+		from, to Dialect
+		want     []Construct
+	}{
+		{
+			spec: "30 0 12 L * ?", from: Quartz, to: Vixie,
+			want: []Construct{{Field: "second", Value: "30"}, {Field: "day-of-month", Value: "L"}},
+		},
+		{
+			spec: "0 0 12 ? * 6#3", from: Quartz, to: Vixie,
+			want: []Construct{{Field: "day-of-week", Value: "6#3"}},
+		},
+		{ // 或的方式组合，不能转换为 #
+			spec: "0 9 1-7 * 1", from: Vixie, to: Quartz,
+			want: []Construct{{Field: "day-of-month or day-of-week", Value: "1-7 1"}},
+		},
+		{
+			spec: "Fri *-*-15..20 12:00", from: Systemd, to: Quartz,
+			want: []Construct{{Field: "day-of-month and day-of-week", Value: "15..20 Fri"}},
+		},
+		{
+			spec: "0 0 12 1,15 * ?", from: Quartz, to: Quartz,
+			want: nil,
+		},
+		{
+			spec: "0 9 1,15 * 1", from: Vixie, to: Systemd,
+			want: []Construct{{Field: "day-of-month or day-of-week", Value: "1,15 1"}},
+		},
+		{
+			spec: "0 9 1,15 * 1", from: Vixie, to: Quartz,
+			want: []Construct{{Field: "day-of-month or day-of-week", Value: "1,15 1"}},
+		},
+		{
+			spec: "0 9 */2 * 1", from: Vixie, to: Scheduled,
+			want: []Construct{{Field: "day-of-month and day-of-week", Value: "*/2 1"}},
+		},
+		{
+			spec: "Sat *-*-1..7 12:00", from: Systemd, to: Vixie,
+			want: []Construct{{Field: "day-of-month and day-of-week", Value: "1..7 Sat"}},
+		},
+		{
+			spec: "2025-*~01 00:00:00 Asia/Shanghai", from: Systemd, to: Scheduled,
+			want: []Construct{{Field: "day-of-month", Value: "~01"}, {Field: "year", Value: "2025"}, {Field: "timezone", Value: "Asia/Shanghai"}},
+		},
+		{
+			spec: "2100-01-01", from: Systemd, to: Quartz,
+			want: []Construct{{Field: "year", Value: "2100"}},
+		},
+		{
+			spec: "*-*~01,03 00:00:00", from: Systemd, to: Quartz,
+			want: []Construct{{Field: "day-of-month", Value: "~01,03"}},
+		},
+		{
+			spec: "@reboot", from: Vixie, to: Systemd,
+			want: []Construct{{Field: "nickname", Value: "@reboot"}},
+		},
+	}
+	for _, item := range errs {
+		e, err := Parse(item.spec, item.from)
+		a.NotError(err, item.spec)
+		s, err := e.Format(item.to)
+		if item.want == nil {
+			a.NotError(err, item.spec)
+			continue
+		}
+
+		var ce *ConvertError
+		a.Empty(s, item.spec).
+			True(errors.As(err, &ce), item.spec).
+			Equal(ce.Dialect, item.to, item.spec).
+			Equal(ce.Constructs, item.want, item.spec)
+	}
+
+	e, err := Parse("* * * * *", Vixie)
+	a.NotError(err)
+	s, err := e.Format(Dialect(10))
+	a.Error(err).Empty(s)
+}
+
+// Quartz 中的 # 和 L 与 systemd 之间的相互转换
+func TestExpr_Format_nth(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string][]time.Time{ // 表达式及其之后的两次执行时间
+		"0 0 12 ? * 6#3": {time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), time.Date(2026, 11, 20, 12, 0, 0, 0, time.UTC)},
+		"0 0 12 ? * 6L":  {time.Date(2026, 10, 30, 12, 0, 0, 0, time.UTC), time.Date(2026, 11, 27, 12, 0, 0, 0, time.UTC)},
+		"0 0 12 ? * 5#5": {time.Date(2026, 10, 29, 12, 0, 0, 0, time.UTC), time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for spec, want := range data {
+		to, err := Convert(spec, Quartz, Systemd)
+		a.NotError(err, spec)
+
+		back, err := Convert(to, Systemd, Quartz)
+		a.NotError(err, to).Equal(back, spec)
+
+		e, err := calendar.Parse(to, time.UTC)
+		a.NotError(err, to)
+		next := e.Next(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+		a.Equal(next, want[0], to)
+		a.Equal(e.Next(next), want[1], to)
+	}
+}
+
+// 转换后的表达式与原表达式应该在相同的时间点触发
+func TestExpr_Format_equivalent(t *testing.T) {
+	a := assert.New(t, false)
+	start := time.Date(2024, 12, 30, 23, 59, 30, 0, time.UTC)
+
+	equal := func(x, y schedulers.Scheduler, spec string) {
+		a.TB().Helper()
+		lx, ly := start, start
+		for range 50 {
+			lx, ly = x.Next(lx), y.Next(ly)
+			a.Equal(lx, ly, spec)
+		}
+	}
+
+	for _, spec := range []string{
+		"0 0 9 * * 1-5",
+		"0 30 8,12,18 1,15 * *",
+		"0 0 0 1 1,4,7,10 *",
+		"15 10-12 0 * * 0",
+		"0 0 12 13 * 5",
+	} {
+		to, err := Convert(spec, Scheduled, Systemd)
+		if spec == "0 0 12 13 * 5" { // 或的方式组合
+			a.Error(err)
+			continue
+		}
+		a.NotError(err, spec)
+
+		c, err := cron.Parse(spec, time.UTC)
+		a.NotError(err, spec)
+		e, err := calendar.Parse(to, time.UTC)
+		a.NotError(err, to)
+		equal(c, e, spec)
+	}
+
+	for _, spec := range []string{
+		"Mon..Fri *-*-* 09:00:00",
+		"*-*-01,15 08,12,18:30:00",
+		"quarterly",
+		"Sun *-*-* 00:10..12:15",
+		"*:0/15",
+	} {
+		to, err := Convert(spec, Systemd, Scheduled)
+		a.NotError(err, spec)
+
+		e, err := calendar.Parse(spec, time.UTC)
+		a.NotError(err, spec)
+		c, err := cron.Parse(to, time.UTC)
+		a.NotError(err, to)
+		equal(e, c, spec)
+	}
+}
@@ -0,0 +1,547 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package dialect
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/calendar"
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+var (
+	monthNames   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+	// systemd 中的星期名称，以周一作为第一天。
+	systemdWeekdays = [][2]string{
+		{"Mon", "monday"}, {"Tue", "tuesday"}, {"Wed", "wednesday"}, {"Thu", "thursday"},
+		{"Fri", "friday"}, {"Sat", "saturday"}, {"Sun", "sunday"},
+	}
+)
+
+// crontab 中的便捷指令，scheduled 中的便捷指令仅多了秒数。
+var nicknames = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// systemd 中的便捷指令
+var shorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+}
+
+// 各格式中字段的取值规则
+var (
+	// 依次为秒、分、时、日、月和星期，vixie 不包含秒。
+	cronRules = []rule{
+		{bound: bounds[secondField]},
+		{bound: bounds[minuteField]},
+		{bound: bounds[hourField]},
+		{bound: bounds[dayField]},
+		{bound: bounds[monthField], names: monthNames},
+		{bound: bound{min: 0, max: 7}, names: weekdayNames, conv: func(v int) int { return v % 7 }},
+	}
+
+	// 依次为秒、分、时、日、月、星期和年。
+	quartzRules = []rule{
+		{bound: bounds[secondField]},
+		{bound: bounds[minuteField]},
+		{bound: bounds[hourField]},
+		{bound: bounds[dayField]},
+		{bound: bounds[monthField], names: monthNames},
+		{bound: bound{min: 1, max: 7}, names: weekdayNames, conv: func(v int) int { return v - 1 }},
+		{bound: bound{min: 1970, max: 2099}},
+	}
+
+	// 依次为秒、分、时、日、月和年，星期只能是名称，由 parseSystemdWeekdays 单独处理。
+	systemdRules = []rule{
+		{bound: bounds[secondField]},
+		{bound: bounds[minuteField]},
+		{bound: bounds[hourField]},
+		{bound: bounds[dayField]},
+		{bound: bounds[monthField]},
+		weekdayField: {},
+		yearField:    {bound: bounds[yearField], year2: true},
+	}
+)
+
+type (
+	// 字段的解析规则
+	rule struct {
+		bound
+		names []string      // 名称，names[i] 表示值 min+i，不区分大小写。
+		conv  func(int) int // 转换为 Expr 中的值，为空表示不需要转换。
+		year2 bool          // 是否允许两位数的年份
+	}
+
+	// 字段的语法
+	syntax struct {
+		sep     string // 范围的分隔符
+		step    bool   // 是否支持 */step 和 n1-n2/step
+		open    bool   // 是否支持 n/step
+		reverse bool   // n/step 是否从 n 往前推算
+	}
+)
+
+var (
+	vixieSyntax     = syntax{sep: "-", step: true}
+	scheduledSyntax = syntax{sep: "-"}
+	quartzSyntax    = syntax{sep: "-", step: true, open: true}
+	systemdSyntax   = syntax{sep: "..", step: true, open: true}
+)
+
+// Parse 解析 d 格式的表达式
+//
+// 返回的错误为 [schedulers.InvalidError]。
+func Parse(spec string, d Dialect) (*Expr, error) {
+	var e *Expr
+	var err error
+	switch d {
+	case Scheduled:
+		e, err = parseScheduled(spec)
+	case Vixie:
+		e, err = parseVixie(spec)
+	case Quartz:
+		e, err = parseQuartz(spec)
+	case Systemd:
+		e, err = parseSystemd(spec)
+	default:
+		err = localeutil.Error("invalid dialect %s", d.String())
+	}
+
+	if err != nil {
+		return nil, &schedulers.InvalidError{Spec: spec, Err: err}
+	}
+
+	e.normalize()
+	return e, nil
+}
+
+// 各字段的内容由 [cron.Parse] 进行验证，此处仅负责转换。
+func parseScheduled(spec string) (*Expr, error) {
+	if _, err := cron.Parse(spec, time.UTC); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case spec == "@reboot":
+		return &Expr{reboot: true}, nil
+	case spec[0] == '@':
+		spec = "0 " + nicknames[spec]
+	}
+
+	e := &Expr{}
+	fs := strings.Fields(spec)
+	if err := e.parseFields(fs, 0, cronRules, scheduledSyntax); err != nil {
+		return nil, err
+	}
+	e.dayOr = fs[dayField] != "*" && fs[weekdayField] != "*"
+	return e, nil
+}
+
+func parseVixie(spec string) (*Expr, error) {
+	if strings.HasPrefix(spec, "@") {
+		if strings.EqualFold(spec, "@reboot") {
+			return &Expr{reboot: true}, nil
+		}
+
+		s, found := nicknames[strings.ToLower(spec)]
+		if !found {
+			return nil, localeutil.Error("invalid direct %s", spec)
+		}
+		spec = s
+	}
+
+	fs := strings.Fields(spec)
+	if len(fs) != 5 {
+		return nil, localeutil.Error("incorrect length")
+	}
+
+	e := &Expr{}
+	e.fields[secondField] = values{0}
+	e.raw[secondField] = "0"
+	if err := e.parseFields(fs, minuteField, cronRules[minuteField:], vixieSyntax); err != nil {
+		return nil, err
+	}
+
+	// 与 vixie cron 的实现相同，只要有一个字段以 * 开头，就是以与的方式组合。
+	e.dayOr = fs[dayField-1][0] != '*' && fs[weekdayField-1][0] != '*'
+	return e, nil
+}
+
+func parseQuartz(spec string) (*Expr, error) {
+	fs := strings.Fields(spec)
+	if len(fs) != 6 && len(fs) != 7 {
+		return nil, localeutil.Error("incorrect length")
+	}
+
+	e := &Expr{}
+	copy(e.raw[:], fs)
+	for i, tok := range fs {
+		if i == dayField || i == weekdayField {
+			continue
+		}
+
+		vals, err := quartzRules[i].parse(tok, quartzSyntax)
+		if err != nil {
+			return nil, err
+		}
+		e.fields[i] = vals
+	}
+
+	// Quartz 要求日期和星期必须有且仅有一个是 ?
+	day, week := fs[dayField], fs[weekdayField]
+	switch {
+	case (day == "?") == (week == "?"):
+		return nil, localeutil.Error("%s conflicts with %s", fieldNames[dayField], fieldNames[weekdayField])
+	case day != "?":
+		return e, e.parseQuartzDay(day)
+	default:
+		return e, e.parseQuartzWeekday(week)
+	}
+}
+
+// L、LW、L-3、15W 以及普通的日期
+func (e *Expr) parseQuartzDay(tok string) error {
+	switch {
+	case tok == "L":
+		e.dayFromEnd = true
+		e.fields[dayField] = values{1}
+	case tok == "LW":
+		e.dayFromEnd, e.nearest = true, true
+		e.fields[dayField] = values{1}
+	case strings.HasPrefix(tok, "L-"):
+		n, err := strconv.Atoi(tok[2:])
+		if err != nil || n < 0 || n > 30 {
+			return invalidValue(tok)
+		}
+		e.dayFromEnd = true
+		e.fields[dayField] = values{n + 1}
+	case strings.HasSuffix(tok, "W"):
+		n, ok := quartzRules[dayField].value(tok[:len(tok)-1])
+		if !ok {
+			return invalidValue(tok)
+		}
+		e.nearest = true
+		e.fields[dayField] = values{n}
+	default:
+		vals, err := quartzRules[dayField].parse(tok, quartzSyntax)
+		if err != nil {
+			return err
+		}
+		e.fields[dayField] = vals
+	}
+	return nil
+}
+
+// L、6L、6#3 以及普通的星期
+func (e *Expr) parseQuartzWeekday(tok string) error {
+	r := quartzRules[weekdayField]
+
+	if tok == "L" {
+		e.fields[weekdayField] = values{int(time.Saturday)}
+		return nil
+	}
+
+	day, nth := tok, 0
+	if d, n, found := strings.Cut(tok, "#"); found {
+		v, err := strconv.Atoi(n)
+		if err != nil || v < 1 || v > 5 {
+			return invalidValue(tok)
+		}
+		day, nth = d, v
+	} else if strings.HasSuffix(tok, "L") {
+		day, nth = tok[:len(tok)-1], -1
+	}
+
+	if nth == 0 {
+		vals, err := r.parse(tok, quartzSyntax)
+		if err != nil {
+			return err
+		}
+		e.fields[weekdayField] = vals
+		return nil
+	}
+
+	v, ok := r.value(day)
+	if !ok {
+		return invalidValue(tok)
+	}
+	e.fields[weekdayField] = values{r.conv(v)}
+	e.nth = nth
+	return nil
+}
+
+// 各字段的内容由 [calendar.Parse] 进行验证，此处仅负责转换。
+func parseSystemd(spec string) (*Expr, error) {
+	if _, err := calendar.Parse(spec, time.UTC); err != nil {
+		return nil, err
+	}
+
+	tokens := strings.Fields(spec)
+	if s, found := shorthands[strings.ToLower(tokens[0])]; found {
+		tokens = append(strings.Fields(s), tokens[1:]...)
+	}
+
+	e := &Expr{}
+	for _, f := range []int{hourField, minuteField, secondField} {
+		e.fields[f] = values{0}
+		e.raw[f] = "00"
+	}
+	e.raw[dayField], e.raw[monthField], e.raw[yearField] = "*", "*", "*"
+
+	if c := tokens[0][0]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		e.parseSystemdWeekdays(tokens[0])
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 && strings.ContainsAny(tokens[0], "-~") {
+		if err := e.parseSystemdDate(tokens[0]); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 && strings.ContainsRune(tokens[0], ':') {
+		fs := strings.Split(tokens[0], ":")
+		if len(fs) == 2 {
+			fs = append(fs, "00")
+		}
+		if err := e.parseFields([]string{fs[2], fs[1], fs[0]}, secondField, systemdRules, systemdSyntax); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 {
+		e.tz = tokens[0]
+	}
+	return e, nil
+}
+
+// 星期的格式已经由 [calendar.Parse] 验证
+func (e *Expr) parseSystemdWeekdays(tok string) {
+	var days [7]bool
+	for _, item := range strings.Split(tok, ",") {
+		if item == "" {
+			continue
+		}
+
+		first, last, found := strings.Cut(item, "..")
+		if !found {
+			first, last, found = strings.Cut(item, "-")
+		}
+		start := systemdWeekday(first)
+		stop := start
+		if found {
+			stop = systemdWeekday(last)
+		}
+
+		for i := start; i <= stop; i++ {
+			days[(i+1)%7] = true // 转换为 time.Weekday
+		}
+	}
+
+	var vals values
+	for d, ok := range days {
+		if ok {
+			vals = append(vals, d)
+		}
+	}
+	if bounds[weekdayField].full(vals) {
+		vals = nil
+	}
+
+	e.fields[weekdayField] = vals
+	e.raw[weekdayField] = tok
+}
+
+func systemdWeekday(name string) int {
+	return slices.IndexFunc(systemdWeekdays, func(names [2]string) bool {
+		return strings.EqualFold(names[0], name) || strings.EqualFold(names[1], name)
+	})
+}
+
+// [年-]月-日 或是 [年-]月~日
+func (e *Expr) parseSystemdDate(s string) error {
+	index := strings.LastIndexAny(s, "-~")
+	e.dayFromEnd = s[index] == '~'
+
+	st := systemdSyntax
+	st.reverse = e.dayFromEnd
+	day := s[index+1:]
+	vals, err := systemdRules[dayField].parse(day, st)
+	if err != nil {
+		return err
+	}
+	e.fields[dayField] = vals
+	e.raw[dayField] = day
+	if e.dayFromEnd {
+		e.raw[dayField] = "~" + day
+	}
+
+	month := s[:index]
+	if y, m, found := strings.Cut(month, "-"); found {
+		if e.fields[yearField], err = systemdRules[yearField].parse(y, systemdSyntax); err != nil {
+			return err
+		}
+		e.raw[yearField] = y
+		month = m
+	}
+	e.fields[monthField], err = systemdRules[monthField].parse(month, systemdSyntax)
+	e.raw[monthField] = month
+	return err
+}
+
+// 依次解析 fs 中的字段，fs[0] 对应 from 字段，rules 中的元素与 fs 一一对应。
+func (e *Expr) parseFields(fs []string, from int, rules []rule, st syntax) error {
+	for i, tok := range fs {
+		vals, err := rules[i].parse(tok, st)
+		if err != nil {
+			return err
+		}
+		e.fields[from+i] = vals
+		e.raw[from+i] = tok
+	}
+	return nil
+}
+
+// 对解析后的内容进行规范化处理
+func (e *Expr) normalize() {
+	if e.fields[dayField] == nil {
+		e.dayFromEnd = false
+	}
+
+	// 以或的方式组合时，只要有一个字段不受限制，就表示所有的日期都符合。
+	if e.dayOr && (!e.restricted(dayField) || !e.restricted(weekdayField)) {
+		e.fields[dayField], e.fields[weekdayField] = nil, nil
+		e.dayFromEnd, e.nearest, e.nth = false, false, 0
+	}
+	if !e.restricted(dayField) || !e.restricted(weekdayField) {
+		e.dayOr = false
+	}
+}
+
+// 将名称或数值转换为值
+func (r rule) value(s string) (int, bool) {
+	if index := slices.IndexFunc(r.names, func(name string) bool { return strings.EqualFold(name, s) }); index >= 0 {
+		return r.min + index, true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	if r.year2 && len(s) == 2 {
+		if n < 70 {
+			n += 2000
+		} else {
+			n += 1900
+		}
+	}
+	return n, r.valid(n)
+}
+
+// 解析以逗号分隔的字段内容
+//
+// 可以是以下格式的组合：
+//
+//	*
+//	n
+//	n1-n2
+//	n1-n2/step
+//	*/step
+//	n/step
+//	n1,n2
+func (r rule) parse(tok string, st syntax) (values, error) {
+	if tok == "*" {
+		return nil, nil
+	}
+
+	var list values
+	for _, item := range strings.Split(tok, ",") {
+		base, rep, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(rep)
+			if !st.step || err != nil || n <= 0 {
+				return nil, invalidValue(item)
+			}
+			step = n
+		}
+
+		start, stop := r.min, r.max
+		down := false
+		if base != "*" {
+			first, last, isRange := strings.Cut(base, st.sep)
+
+			var ok bool
+			if start, ok = r.value(first); !ok {
+				return nil, invalidValue(item)
+			}
+
+			switch {
+			case isRange:
+				if stop, ok = r.value(last); !ok || stop < start {
+					return nil, invalidValue(item)
+				}
+			case !hasStep:
+				stop = start
+			case !st.open:
+				return nil, invalidValue(item)
+			case st.reverse:
+				down = true
+			}
+		} else if hasStep && st.reverse {
+			stop = start
+		}
+
+		if down {
+			for v := start; v >= r.min; v -= step {
+				list = append(list, r.convert(v))
+			}
+		} else {
+			for v := start; v <= stop; v += step {
+				list = append(list, r.convert(v))
+			}
+		}
+	}
+
+	slices.Sort(list)
+	list = slices.Compact(list)
+	if len(list) == r.max-r.min+1 || (r.conv != nil && len(list) == 7) { // 星期中的 0 和 7 是同一天
+		return nil, nil
+	}
+	return list, nil
+}
+
+func (r rule) convert(v int) int {
+	if r.conv != nil {
+		return r.conv(v)
+	}
+	return v
+}
+
+func invalidValue(v string) error { return localeutil.Error("invalid value %s", v) }
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package dialect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func TestRule_parse(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		rule rule
+		tok  string
+		st   syntax
+		want values
+	}{
+		{rule: cronRules[minuteField], tok: "*", st: vixieSyntax, want: nil},
+		{rule: cronRules[minuteField], tok: "5", st: vixieSyntax, want: values{5}},
+		{rule: cronRules[minuteField], tok: "1-3,10", st: vixieSyntax, want: values{1, 2, 3, 10}},
+		{rule: cronRules[minuteField], tok: "*/20", st: vixieSyntax, want: values{0, 20, 40}},
+		{rule: cronRules[minuteField], tok: "10-30/10", st: vixieSyntax, want: values{10, 20, 30}},
+		{rule: cronRules[minuteField], tok: "0-59", st: vixieSyntax, want: nil},
+		{rule: cronRules[monthField], tok: "jan,MAR-may", st: vixieSyntax, want: values{1, 3, 4, 5}},
+		{rule: cronRules[weekdayField], tok: "7", st: vixieSyntax, want: values{0}},
+		{rule: cronRules[weekdayField], tok: "1-7", st: vixieSyntax, want: nil},
+		{rule: cronRules[weekdayField], tok: "sat,SUN", st: vixieSyntax, want: values{0, 6}},
+		{rule: quartzRules[minuteField], tok: "5/15", st: quartzSyntax, want: values{5, 20, 35, 50}},
+		{rule: quartzRules[weekdayField], tok: "1,7", st: quartzSyntax, want: values{0, 6}},
+		{rule: quartzRules[weekdayField], tok: "MON-FRI", st: quartzSyntax, want: values{1, 2, 3, 4, 5}},
+		{rule: systemdRules[hourField], tok: "08..10,20", st: systemdSyntax, want: values{8, 9, 10, 20}},
+		{rule: systemdRules[yearField], tok: "25", st: systemdSyntax, want: values{2025}},
+		{rule: systemdRules[dayField], tok: "07/2", st: syntax{sep: "..", step: true, open: true, reverse: true}, want: values{1, 3, 5, 7}},
+	}
+	for _, item := range data {
+		vals, err := item.rule.parse(item.tok, item.st)
+		a.NotError(err, item.tok).Equal(vals, item.want, item.tok)
+	}
+
+	errs := []*struct {
+		rule rule
+		tok  string
+		st   syntax
+	}{
+		{rule: cronRules[minuteField], tok: "60", st: vixieSyntax},
+		{rule: cronRules[minuteField], tok: "5/15", st: vixieSyntax},
+		{rule: cronRules[minuteField], tok: "*/0", st: vixieSyntax},
+		{rule: cronRules[minuteField], tok: "10-5", st: vixieSyntax},
+		{rule: cronRules[minuteField], tok: "1,", st: vixieSyntax},
+		{rule: cronRules[minuteField], tok: "*/5", st: scheduledSyntax},
+		{rule: cronRules[monthField], tok: "foo", st: vixieSyntax},
+		{rule: quartzRules[weekdayField], tok: "0", st: quartzSyntax},
+	}
+	for _, item := range errs {
+		vals, err := item.rule.parse(item.tok, item.st)
+		a.Error(err, item.tok).Nil(vals, item.tok)
+	}
+}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+
+	// scheduled
+	e, err := Parse("5 30 9 1,15 * 1-5", Scheduled)
+	a.NotError(err).
+		Equal(e.fields[secondField], values{5}).
+		Equal(e.fields[dayField], values{1, 15}).
+		Equal(e.fields[weekdayField], values{1, 2, 3, 4, 5}).
+		True(e.dayOr)
+
+	e, err = Parse("@daily", Scheduled)
+	a.NotError(err).
+		Equal(e.fields[secondField], values{0}).
+		Equal(e.fields[hourField], values{0}).
+		Nil(e.fields[dayField])
+
+	e, err = Parse("@reboot", Scheduled)
+	a.NotError(err).True(e.reboot)
+
+	// vixie
+	e, err = Parse("0 9 */2 * 1", Vixie)
+	a.NotError(err).
+		Equal(e.fields[secondField], values{0}).
+		False(e.dayOr).True(e.dayAnd())
+
+	// 或的方式组合，其中一个字段为全部值，表示所有日期。
+	e, err = Parse("0 9 1-31 * 1", Vixie)
+	a.NotError(err).
+		Nil(e.fields[dayField]).
+		Nil(e.fields[weekdayField]).
+		False(e.dayOr)
+
+	e, err = Parse("@REBOOT", Vixie)
+	a.NotError(err).True(e.reboot)
+
+	// quartz
+	e, err = Parse("0 0 12 L-3 * ?", Quartz)
+	a.NotError(err).True(e.dayFromEnd).Equal(e.fields[dayField], values{4})
+
+	e, err = Parse("0 0 12 LW * ?", Quartz)
+	a.NotError(err).True(e.dayFromEnd).True(e.nearest).Equal(e.fields[dayField], values{1})
+
+	e, err = Parse("0 0 12 ? * FRI#3", Quartz)
+	a.NotError(err).Equal(e.nth, 3).Equal(e.fields[weekdayField], values{int(time.Friday)})
+
+	e, err = Parse("0 0 12 ? * 6L", Quartz)
+	a.NotError(err).Equal(e.nth, -1).Equal(e.fields[weekdayField], values{int(time.Friday)})
+
+	e, err = Parse("0 0 12 ? * L 2030-2032", Quartz)
+	a.NotError(err).
+		Equal(e.fields[weekdayField], values{int(time.Saturday)}).
+		Equal(e.fields[yearField], values{2030, 2031, 2032})
+
+	// systemd
+	e, err = Parse("Mon,Wed..Fri 2025-*~07/2 08:30 UTC", Systemd)
+	a.NotError(err).
+		Equal(e.fields[weekdayField], values{1, 3, 4, 5}).
+		Equal(e.fields[yearField], values{2025}).
+		True(e.dayFromEnd).
+		Equal(e.fields[dayField], values{1, 3, 5, 7}).
+		Equal(e.fields[secondField], values{0}).
+		Equal(e.raw[dayField], "~07/2").
+		Equal(e.tz, "UTC")
+
+	e, err = Parse("weekly", Systemd)
+	a.NotError(err).Equal(e.fields[weekdayField], values{1})
+
+	errs := map[string]Dialect{
+		"":                    Scheduled,
+		"* * * * * *":         Scheduled,
+		"0 0 * * *":           Scheduled,
+		"* * * *":             Vixie,
+		"@every":              Vixie,
+		"0 0 L * *":           Vixie,
+		"0 0 12 * * *":        Quartz,
+		"0 0 12 ? * ?":        Quartz,
+		"0 0 12 L-31 * ?":     Quartz,
+		"0 0 12 ? * 6#6":      Quartz,
+		"0 0 12 ? * 8L":       Quartz,
+		"0 0 12 32W * ?":      Quartz,
+		"0 0 12 ? * * 2100":   Quartz,
+		"0 0 12 ? * * * * ":   Quartz,
+		"Foo *-*-* 00:00:00":  Systemd,
+		"*-*-* 25:00:00":      Systemd,
+		"*-*-* 00:00:00 Mars": Systemd,
+		"* * * * *":           Dialect(10),
+	}
+	for spec, d := range errs {
+		e, err := Parse(spec, d)
+		var ie *schedulers.InvalidError
+		a.True(errors.As(err, &ie), spec).Nil(e, spec)
+	}
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import "github.com/issue9/localeutil"
+
+// InvalidError 无效的调度规则
+//
+// 由各个返回 error 的构造函数返回，可以通过 [errors.As] 进行判断。
+type InvalidError struct {
+	Spec string // 调度规则的文本表示
+	Err  error  // 具体的错误原因
+}
+
+func (err *InvalidError) Error() string { return err.LocaleString(nil) }
+
+func (err *InvalidError) LocaleString(p *localeutil.Printer) string {
+	var msg string
+	if s, ok := err.Err.(localeutil.Stringer); ok {
+		msg = s.LocaleString(p)
+	} else {
+		msg = err.Err.Error()
+	}
+	return localeutil.Phrase("invalid schedule %s: %s", err.Spec, msg).LocaleString(p)
+}
+
+func (err *InvalidError) Unwrap() error { return err.Err }
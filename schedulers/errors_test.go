@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+	"github.com/issue9/localeutil"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestInvalidError(t *testing.T) {
+	a := assert.New(t, false)
+
+	var err error = &InvalidError{Spec: "* * *", Err: fs.ErrNotExist}
+	a.Equal(err.Error(), "invalid schedule * * *: file does not exist").
+		ErrorIs(err, fs.ErrNotExist)
+
+	var target *InvalidError
+	a.True(errors.As(err, &target)).Equal(target.Spec, "* * *")
+
+	b := catalog.NewBuilder()
+	a.NotError(b.SetString(language.SimplifiedChinese, "invalid schedule %s: %s", "无效的调度规则 %s：%s"))
+	a.NotError(b.SetString(language.SimplifiedChinese, "invalid value %s", "无效的值 %s"))
+	p := message.NewPrinter(language.SimplifiedChinese, message.Catalog(b))
+
+	err = &InvalidError{Spec: "-1s", Err: localeutil.Error("invalid value %s", "-1s")}
+	a.Equal(err.(localeutil.Stringer).LocaleString(p), "无效的调度规则 -1s：无效的值 -1s").
+		Equal(err.Error(), "invalid schedule -1s: invalid value -1s")
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package exclude 按日期排除调度时间
+//
+// 与 Quartz 中的 Calendar 概念相同，通过 [Calendar] 指定需要排除的日期，
+// 比如节假日等，再由 [Wrap] 从调度器中跳过这些日期。
+package exclude
+
+import (
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Calendar 日历
+//
+// 用于判断某个时间点所在的日期是否被排除。
+type Calendar interface {
+	// Excluded t 所在的日期是否被排除
+	//
+	// 日期以 t 本身的时区为准。
+	Excluded(t time.Time) bool
+}
+
+// CalendarFunc 将函数转换为 [Calendar] 接口
+type CalendarFunc func(time.Time) bool
+
+// MonthDay 表示每年中的某一天
+type MonthDay struct {
+	Month time.Month
+	Day   int
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type calendar struct {
+	dates  map[date]struct{}
+	annual map[MonthDay]struct{}
+	weekly [7]bool
+}
+
+type union []Calendar
+
+func (f CalendarFunc) Excluded(t time.Time) bool { return f(t) }
+
+func newCalendar() *calendar {
+	return &calendar{
+		dates:  make(map[date]struct{}, 10),
+		annual: make(map[MonthDay]struct{}, 10),
+	}
+}
+
+// Dates 排除指定的日期
+//
+// 仅采用 dates 中各元素的年月日部分。
+func Dates(dates ...time.Time) Calendar {
+	c := newCalendar()
+	for _, t := range dates {
+		y, m, d := t.Date()
+		c.dates[date{year: y, month: m, day: d}] = struct{}{}
+	}
+	return c
+}
+
+// Annual 排除每年固定的日期
+func Annual(days ...MonthDay) Calendar {
+	c := newCalendar()
+	for _, d := range days {
+		c.annual[d] = struct{}{}
+	}
+	return c
+}
+
+// Weekly 排除每周固定的星期
+func Weekly(days ...time.Weekday) Calendar {
+	c := newCalendar()
+	for _, d := range days {
+		c.weekly[d] = true
+	}
+	return c
+}
+
+// Union 合并多个日历
+//
+// 任意一个日历排除的日期，都会被排除。
+func Union(cals ...Calendar) Calendar { return union(cals) }
+
+func (c *calendar) Excluded(t time.Time) bool {
+	if c.weekly[t.Weekday()] {
+		return true
+	}
+
+	y, m, d := t.Date()
+	if _, found := c.annual[MonthDay{Month: m, Day: d}]; found {
+		return true
+	}
+	_, found := c.dates[date{year: y, month: m, day: d}]
+	return found
+}
+
+func (u union) Excluded(t time.Time) bool {
+	for _, c := range u {
+		if c.Excluded(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap 从 s 中跳过 cal 所排除的日期
+//
+// 被排除日期中的时间点会被跳过，具体规则可参考 [schedulers.FilterDays]。
+func Wrap(s schedulers.Scheduler, cal Calendar) schedulers.Scheduler {
+	return schedulers.FilterDays(s, nil, func(t time.Time) bool { return !cal.Excluded(t) })
+}
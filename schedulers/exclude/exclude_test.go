@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package exclude
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+var (
+	_ Calendar = CalendarFunc(nil)
+	_ Calendar = &calendar{}
+	_ Calendar = union{}
+)
+
+func TestCalendar(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := Dates(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	a.True(c.Excluded(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))).
+		True(c.Excluded(time.Date(2026, 10, 1, 23, 59, 59, 0, time.UTC))).
+		False(c.Excluded(time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC))).
+		False(c.Excluded(time.Date(2027, 10, 1, 0, 0, 0, 0, time.UTC)))
+
+	c = Annual(MonthDay{Month: time.December, Day: 25})
+	a.True(c.Excluded(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC))).
+		True(c.Excluded(time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC))).
+		False(c.Excluded(time.Date(2030, 12, 26, 0, 0, 0, 0, time.UTC)))
+
+	c = Weekly(time.Saturday, time.Sunday)
+	a.True(c.Excluded(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))).
+		True(c.Excluded(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC))).
+		False(c.Excluded(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)))
+
+	c = Union(Weekly(time.Saturday), Annual(MonthDay{Month: time.January, Day: 1}))
+	a.True(c.Excluded(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))).
+		True(c.Excluded(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))).
+		False(c.Excluded(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	// 以 t 的时区为准
+	loc := time.FixedZone("UTC+8", 8*3600)
+	c = Dates(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	a.False(c.Excluded(time.Date(2026, 10, 2, 1, 0, 0, 0, loc))).
+		True(c.Excluded(time.Date(2026, 10, 1, 1, 0, 0, 0, loc)))
+}
+
+func TestWrap(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := cron.Parse("0 0 9 * * *", time.UTC)
+	a.NotError(err).NotNil(s)
+
+	s = Wrap(s, Union(Weekly(time.Saturday, time.Sunday), Dates(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))))
+
+	// 2026-10-16 为周五
+	next := s.Next(time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC))
+
+	// 所有日期都被排除
+	s, err = cron.Parse("0 0 9 * * *", time.UTC)
+	a.NotError(err).NotNil(s)
+	s = Wrap(s, CalendarFunc(func(time.Time) bool { return true }))
+	a.True(s.Next(time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)).IsZero())
+}
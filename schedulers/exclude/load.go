@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package exclude
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	monthDayLayout = "01-02"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+type calendarJSON struct {
+	Dates  []string `json:"dates,omitempty"`
+	Annual []string `json:"annual,omitempty"`
+	Weekly []string `json:"weekly,omitempty"`
+}
+
+// LoadFile 从文件中加载日历
+//
+// 扩展名为 .json 的文件采用 [LoadJSON] 加载，其它的采用 [Load] 加载。
+func LoadFile(fsys fs.FS, name string) (Calendar, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(path.Ext(name), ".json") {
+		return LoadJSON(f)
+	}
+	return Load(f)
+}
+
+// Load 从文本内容中加载日历
+//
+// 每行表示一条规则，# 之后的内容为注释，可以是以下格式：
+//
+//	2026-10-01 # 指定的日期
+//	12-25      # 每年的 12 月 25 日
+//	sat        # 每周六，也可以是 saturday，不区分大小写。
+func Load(r io.Reader) (Calendar, error) {
+	c := newCalendar()
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if index := strings.IndexByte(text, '#'); index >= 0 {
+			text = text[:index]
+		}
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+
+		if !c.addWeekday(text) && !c.addDate(text) && !c.addAnnual(text) {
+			return nil, localeutil.Error("invalid calendar rule %s at line %d", text, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadJSON 从 JSON 内容中加载日历
+//
+// 格式如下：
+//
+//	{
+//	    "dates": ["2026-10-01"],
+//	    "annual": ["12-25"],
+//	    "weekly": ["sat", "sun"]
+//	}
+func LoadJSON(r io.Reader) (Calendar, error) {
+	data := &calendarJSON{}
+	if err := json.NewDecoder(r).Decode(data); err != nil {
+		return nil, err
+	}
+
+	c := newCalendar()
+	for _, v := range data.Dates {
+		if !c.addDate(v) {
+			return nil, localeutil.Error("invalid calendar rule %s", v)
+		}
+	}
+	for _, v := range data.Annual {
+		if !c.addAnnual(v) {
+			return nil, localeutil.Error("invalid calendar rule %s", v)
+		}
+	}
+	for _, v := range data.Weekly {
+		if !c.addWeekday(v) {
+			return nil, localeutil.Error("invalid calendar rule %s", v)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *calendar) addWeekday(v string) bool {
+	w, found := weekdays[strings.ToLower(v)]
+	if found {
+		c.weekly[w] = true
+	}
+	return found
+}
+
+func (c *calendar) addDate(v string) bool {
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return false
+	}
+
+	y, m, d := t.Date()
+	c.dates[date{year: y, month: m, day: d}] = struct{}{}
+	return true
+}
+
+func (c *calendar) addAnnual(v string) bool {
+	t, err := time.Parse(monthDayLayout, v)
+	if err != nil {
+		return false
+	}
+
+	c.annual[MonthDay{Month: t.Month(), Day: t.Day()}] = struct{}{}
+	return true
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package exclude
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestLoadFile(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, name := range []string{"holidays.txt", "holidays.json"} {
+		c, err := LoadFile(os.DirFS("./testdata"), name)
+		a.NotError(err, name).NotNil(c, name)
+
+		a.True(c.Excluded(time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)), name). // sat
+												True(c.Excluded(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)), name). // sun
+												True(c.Excluded(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)), name).
+												True(c.Excluded(time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)), name).
+												True(c.Excluded(time.Date(2027, 12, 25, 9, 0, 0, 0, time.UTC)), name).
+												False(c.Excluded(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)), name).
+												False(c.Excluded(time.Date(2027, 10, 1, 9, 0, 0, 0, time.UTC)), name)
+	}
+
+	c, err := LoadFile(os.DirFS("./testdata"), "not-exists.txt")
+	a.Error(err).Nil(c)
+}
+
+func TestLoad(t *testing.T) {
+	a := assert.New(t, false)
+
+	c, err := Load(strings.NewReader("\n# comment\n02-29\n"))
+	a.NotError(err).NotNil(c)
+	a.True(c.Excluded(time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)))
+
+	c, err = Load(strings.NewReader("sat\n2026-13-01\n"))
+	a.ErrorString(err, "2026-13-01 at line 2").Nil(c)
+
+	c, err = Load(strings.NewReader("holiday"))
+	a.Error(err).Nil(c)
+}
+
+func TestLoadJSON(t *testing.T) {
+	a := assert.New(t, false)
+
+	c, err := LoadJSON(strings.NewReader(`{"weekly":["sat"]}`))
+	a.NotError(err).NotNil(c)
+	a.True(c.Excluded(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)))
+
+	// 类型不匹配
+	c, err = LoadJSON(strings.NewReader(`{"dates":["12-25"]}`))
+	a.ErrorString(err, "12-25").Nil(c)
+
+	c, err = LoadJSON(strings.NewReader(`{"annual":["2026-12-25"]}`))
+	a.Error(err).Nil(c)
+
+	c, err = LoadJSON(strings.NewReader(`{"weekly":["sat1"]}`))
+	a.Error(err).Nil(c)
+
+	c, err = LoadJSON(strings.NewReader(`{`))
+	a.Error(err).Nil(c)
+}
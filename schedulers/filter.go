@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import "time"
+
+// MaxSkip [Filter] 中允许连续跳过的最大次数
+//
+// 超过此值，将被视为调度已经终结。
+const MaxSkip = 1 << 22
+
+// MaxSkipDays [FilterDays] 中允许连续跳过的最大天数
+//
+// 超过此值，将被视为调度已经终结。
+const MaxSkipDays = 1 << 14
+
+// Filter 对 s 生成的时间进行过滤
+//
+// 仅保留 accept 返回 true 的时间点，其它时间点会被跳过，
+// 并以被跳过的时间点作为 last 参数继续向 s 获取下一个时间。
+// 如果连续跳过的次数超过 [MaxSkip]，则返回零值，表示调度已经终结。
+//
+// 每次只跳过一个时间点，如果 accept 是以日期为单位进行判断的，应该使用 [FilterDays]。
+func Filter(s Scheduler, accept func(time.Time) bool) Scheduler {
+	return SchedulerFunc(func(last time.Time) time.Time {
+		next := s.Next(last)
+		for i := 0; !next.IsZero() && !accept(next); i++ {
+			if i >= MaxSkip {
+				return time.Time{}
+			}
+			next = s.Next(next)
+		}
+		return next
+	})
+}
+
+// FilterDays 以日期为单位对 s 生成的时间进行过滤
+//
+// 与 [Filter] 相同，但是 accept 返回 false 时，会直接跳过该时间点所在的整天，
+// 以次日零点之前的时间作为 last 参数继续向 s 获取下一个时间。
+// 适用于节假日等以日期为单位的判断，即使连续多日都被排除也能很快得到结果。
+//
+// loc 为计算日期时采用的时区，为 nil 表示采用时间点本身的时区。
+func FilterDays(s Scheduler, loc *time.Location, accept func(time.Time) bool) Scheduler {
+	return SchedulerFunc(func(last time.Time) time.Time {
+		next := s.Next(last)
+		for i := 0; !next.IsZero() && !accept(next); i++ {
+			if i >= MaxSkipDays {
+				return time.Time{}
+			}
+
+			t := next
+			if loc != nil {
+				t = t.In(loc)
+			}
+			y, m, d := t.Date()
+			end := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+			next = s.Next(end.Add(-time.Nanosecond))
+		}
+		return next
+	})
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestFilter(t *testing.T) {
+	a := assert.New(t, false)
+
+	hourly := SchedulerFunc(func(last time.Time) time.Time { return last.Truncate(time.Hour).Add(time.Hour) })
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	s := Filter(hourly, func(t time.Time) bool { return t.Hour()%2 == 0 })
+	next := s.Next(start)
+	a.Equal(next, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 1, 1, 4, 0, 0, 0, time.UTC))
+	a.Equal(s.Next(start), time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)) // 相同的 last 返回相同的值
+
+	// 永远不符合条件
+	s = Filter(hourly, func(time.Time) bool { return false })
+	a.True(s.Next(start).IsZero())
+
+	// 原调度已经终结
+	s = Filter(SchedulerFunc(func(time.Time) time.Time { return time.Time{} }), func(time.Time) bool { return true })
+	a.True(s.Next(start).IsZero())
+}
+
+func TestFilterDays(t *testing.T) {
+	a := assert.New(t, false)
+
+	var calls int
+	hourly := SchedulerFunc(func(last time.Time) time.Time {
+		calls++
+		return last.Truncate(time.Hour).Add(time.Hour)
+	})
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	// 跳过单数日，次日零点的时间点不会被跳过。
+	s := FilterDays(hourly, nil, func(t time.Time) bool { return t.Day()%2 == 0 })
+	next := s.Next(start)
+	a.Equal(next, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)).
+		Equal(calls, 2) // 整天跳过，而不是逐小时跳过。
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC))
+
+	// 以 loc 计算日期
+	loc := time.FixedZone("UTC+8", 8*3600)
+	s = FilterDays(hourly, loc, func(t time.Time) bool { return t.In(loc).Day()%2 == 0 })
+	a.Equal(s.Next(start).UTC(), time.Date(2026, 1, 1, 16, 0, 0, 0, time.UTC)) // UTC+8 的 1 月 2 日零点
+
+	// 永远不符合条件
+	calls = 0
+	s = FilterDays(hourly, nil, func(time.Time) bool { return false })
+	a.True(s.Next(start).IsZero()).
+		Equal(calls, MaxSkipDays+1)
+
+	// 原调度已经终结
+	s = FilterDays(SchedulerFunc(func(time.Time) time.Time { return time.Time{} }), nil, func(time.Time) bool { return true })
+	a.True(s.Next(start).IsZero())
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package holiday 法定节假日与调休的日历
+//
+// 节假日数据以年为单位保存在本地的 JSON 文件中，格式如下：
+//
+//	{
+//	    "year": 2025,
+//	    "holidays": [
+//	        {
+//	            "name": "春节",
+//	            "start": "2025-01-28",
+//	            "end": "2025-02-04",
+//	            "workdays": ["2025-01-26", "2025-02-08"]
+//	        }
+//	    ]
+//	}
+//
+// 其中 end 可以为空，表示仅 start 一天；workdays 表示因该节日而调休的工作日。
+package holiday
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"slices"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar 节假日日历
+//
+// 没有数据的年份，以周一至周五为工作日，周六和周日为休息日。
+type Calendar struct {
+	loc      *time.Location
+	years    []int
+	holidays map[date]string // 节假日及其名称
+	workdays map[date]string // 调休的工作日及其对应的节日名称
+	makeups  []date          // 按顺序保存的 workdays
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type yearJSON struct {
+	Year     int            `json:"year"`
+	Holidays []*holidayJSON `json:"holidays"`
+}
+
+type holidayJSON struct {
+	Name     string   `json:"name"`
+	Start    string   `json:"start"`
+	End      string   `json:"end,omitempty"`
+	Workdays []string `json:"workdays,omitempty"`
+}
+
+// New 声明空的 [Calendar]
+//
+// loc 表示节假日数据所在的时区，判断某个时间点时，会先转换到该时区。
+// 若为 nil，则采用 [time.Local]。
+func New(loc *time.Location) *Calendar {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &Calendar{
+		loc:      loc,
+		holidays: make(map[date]string, 50),
+		workdays: make(map[date]string, 20),
+	}
+}
+
+// Load 加载 fsys 根目录下所有的 JSON 文件
+//
+// loc 的说明可参考 [New]。
+func Load(fsys fs.FS, loc *time.Location) (*Calendar, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := New(loc)
+	for _, name := range names {
+		if err := c.loadFile(fsys, name); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Calendar) loadFile(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Append(f)
+}
+
+// Append 从 r 中读取一年的节假日数据
+//
+// 该操作并不是协程安全的，应该在使用 [Calendar] 之前完成所有数据的加载。
+func (c *Calendar) Append(r io.Reader) error {
+	data := &yearJSON{}
+	if err := json.NewDecoder(r).Decode(data); err != nil {
+		return err
+	}
+
+	for _, h := range data.Holidays {
+		start, err := parseDate(h.Start)
+		if err != nil {
+			return err
+		}
+
+		end := start
+		if h.End != "" {
+			if end, err = parseDate(h.End); err != nil {
+				return err
+			}
+		}
+		if end.Before(start) {
+			return localeutil.Error("invalid holiday %s", h.Name)
+		}
+
+		for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+			c.holidays[dateOf(t)] = h.Name
+		}
+
+		for _, w := range h.Workdays {
+			t, err := parseDate(w)
+			if err != nil {
+				return err
+			}
+
+			d := dateOf(t)
+			if _, found := c.workdays[d]; !found {
+				c.makeups = append(c.makeups, d)
+			}
+			c.workdays[d] = h.Name
+		}
+	}
+	slices.SortFunc(c.makeups, compareDate)
+
+	if !slices.Contains(c.years, data.Year) {
+		c.years = append(c.years, data.Year)
+		slices.Sort(c.years)
+	}
+
+	return nil
+}
+
+// Location 节假日数据所在的时区
+func (c *Calendar) Location() *time.Location { return c.loc }
+
+// Years 返回已经加载数据的年份
+func (c *Calendar) Years() []int { return slices.Clone(c.years) }
+
+// IsWorkday t 所在的日期是否为工作日
+//
+// 调休的工作日返回 true，法定节假日返回 false，其它日期以周一至周五为工作日。
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	t = t.In(c.loc)
+	d := dateOf(t)
+
+	if _, found := c.workdays[d]; found {
+		return true
+	}
+	if _, found := c.holidays[d]; found {
+		return false
+	}
+
+	w := t.Weekday()
+	return w != time.Saturday && w != time.Sunday
+}
+
+// IsHoliday t 所在的日期是否为法定节假日
+//
+// 仅表示数据中指定的节假日，普通的周末并不包含在内。
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	_, found := c.holidays[dateOf(t.In(c.loc))]
+	return found
+}
+
+// Name 返回 t 所在日期对应的节日名称
+//
+// 如果是调休的工作日，返回的是其对应节日的名称；如果都不是，返回空值。
+func (c *Calendar) Name(t time.Time) string {
+	d := dateOf(t.In(c.loc))
+	if name, found := c.holidays[d]; found {
+		return name
+	}
+	return c.workdays[d]
+}
+
+func parseDate(v string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return time.Time{}, localeutil.Error("invalid date %s", v)
+	}
+	return t, nil
+}
+
+func dateOf(t time.Time) date {
+	y, m, d := t.Date()
+	return date{year: y, month: m, day: d}
+}
+
+func compareDate(a, b date) int {
+	switch {
+	case a.year != b.year:
+		return a.year - b.year
+	case a.month != b.month:
+		return int(a.month - b.month)
+	default:
+		return a.day - b.day
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package holiday
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+var cst = time.FixedZone("CST", 8*3600)
+
+func loadCalendar(a *assert.Assertion) *Calendar {
+	c, err := Load(os.DirFS("./testdata"), cst)
+	a.NotError(err).NotNil(c)
+	return c
+}
+
+func TestLoad(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := loadCalendar(a)
+	a.Equal(c.Years(), []int{2025}).
+		Equal(c.Location(), cst).
+		Length(c.makeups, 5).
+		Equal(c.makeups[0], date{year: 2025, month: time.January, day: 26}).
+		Equal(c.makeups[4], date{year: 2025, month: time.October, day: 11})
+
+	c = New(nil)
+	a.Equal(c.Location(), time.Local)
+
+	a.ErrorString(c.Append(strings.NewReader(`{"year":2025,"holidays":[{"name":"h","start":"2025-13-01"}]}`)), "2025-13-01")
+	a.ErrorString(c.Append(strings.NewReader(`{"year":2025,"holidays":[{"name":"h","start":"2025-01-01","end":"2025-02-30"}]}`)), "2025-02-30")
+	a.ErrorString(c.Append(strings.NewReader(`{"year":2025,"holidays":[{"name":"h","start":"2025-01-01","workdays":["2025"]}]}`)), "2025")
+	a.ErrorString(c.Append(strings.NewReader(`{"year":2025,"holidays":[{"name":"h","start":"2025-01-02","end":"2025-01-01"}]}`)), "h")
+	a.Error(c.Append(strings.NewReader(`{`)))
+}
+
+func TestCalendar(t *testing.T) {
+	a := assert.New(t, false)
+	c := loadCalendar(a)
+
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, cst) }
+
+	a.True(c.IsWorkday(day(2025, time.January, 26))) // 周日调休
+
+	a.False(c.IsWorkday(day(2025, time.January, 28))).
+		True(c.IsHoliday(day(2025, time.January, 28)))
+
+	// 春节中的周日
+	a.False(c.IsWorkday(day(2025, time.February, 2))).
+		True(c.IsHoliday(day(2025, time.February, 2)))
+
+	a.True(c.IsWorkday(day(2025, time.February, 5))).
+		False(c.IsHoliday(day(2025, time.February, 5)))
+
+	// 普通周日
+	a.False(c.IsWorkday(day(2025, time.February, 9))).
+		False(c.IsHoliday(day(2025, time.February, 9)))
+
+	// 没有数据的年份
+	a.True(c.IsWorkday(day(2030, time.January, 1))).
+		False(c.IsWorkday(day(2030, time.January, 5)))
+
+	a.Equal(c.Name(day(2025, time.January, 26)), "春节").
+		Equal(c.Name(day(2025, time.October, 8)), "国庆节、中秋节").
+		Empty(c.Name(day(2025, time.October, 9)))
+
+	// 以 c.Location() 为准
+	a.False(c.IsWorkday(time.Date(2025, 9, 30, 16, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package holiday
+
+import (
+	"slices"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+type workday struct {
+	s schedulers.Scheduler
+	f schedulers.Scheduler // 过滤掉非工作日之后的 s
+	c *Calendar
+}
+
+// Workday 仅在工作日执行的调度器
+//
+// s 在非工作日的时间点将被跳过。对于调休的工作日，即使 s 当天并不执行，
+// 比如 0 0 9 * * 1-5 不包含周末，也会以 s 在其之后第一个周一至周五的执行时间，
+// 在调休日执行，使 s 与官方的工作日安排保持一致。
+//
+// 由于会以不同的参数多次调用 s.Next，s 应该是与状态无关的调度器，比如 cron.Parse 的返回值。
+func Workday(s schedulers.Scheduler, c *Calendar) schedulers.Scheduler {
+	return &workday{
+		s: s,
+		f: schedulers.FilterDays(s, c.loc, c.IsWorkday),
+		c: c,
+	}
+}
+
+// Holiday 仅在法定节假日执行的调度器
+//
+// s 在非法定节假日的时间点将被跳过，普通的周末也会被跳过。
+func Holiday(s schedulers.Scheduler, c *Calendar) schedulers.Scheduler {
+	return schedulers.FilterDays(s, c.loc, c.IsHoliday)
+}
+
+func (w *workday) Next(last time.Time) time.Time {
+	next := w.f.Next(last)
+
+	var end date
+	if !next.IsZero() {
+		end = dateOf(next.In(w.c.loc))
+	}
+
+	// 查找 last 与 next 之间的调休日，调休日是有序的，第一个符合要求的即是结果。
+	i, _ := slices.BinarySearchFunc(w.c.makeups, dateOf(last.In(w.c.loc)), compareDate)
+	for ; i < len(w.c.makeups); i++ {
+		d := w.c.makeups[i]
+		if !next.IsZero() && compareDate(d, end) > 0 {
+			break
+		}
+
+		if t := w.makeup(d, last); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			return t
+		}
+	}
+
+	return next
+}
+
+// 计算调休日 d 中在 last 之后的第一个执行时间
+//
+// 以 d 之后的第一个周一至周五作为参照日，将 s 在参照日的执行时间移至 d。
+func (w *workday) makeup(d date, last time.Time) time.Time {
+	loc := w.c.loc
+	day := time.Date(d.year, d.month, d.day, 0, 0, 0, 0, loc)
+
+	var offset int
+	switch day.Weekday() {
+	case time.Saturday:
+		offset = 2
+	case time.Sunday:
+		offset = 1
+	}
+	ref := day.AddDate(0, 0, offset)
+	refEnd := ref.AddDate(0, 0, 1)
+
+	prev := ref.Add(-time.Nanosecond)
+	for t := w.s.Next(prev); !t.IsZero() && t.After(prev) && t.Before(refEnd); t = w.s.Next(t) {
+		prev = t
+
+		t = t.In(loc)
+		h, m, s := t.Clock()
+		if ret := time.Date(d.year, d.month, d.day, h, m, s, t.Nanosecond(), loc); ret.After(last) {
+			return ret
+		}
+	}
+
+	return time.Time{}
+}
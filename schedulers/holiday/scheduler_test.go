@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package holiday
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+func TestWorkday(t *testing.T) {
+	a := assert.New(t, false)
+	c := loadCalendar(a)
+
+	s, err := cron.Parse("0 0 9 * * 1-5", cst)
+	a.NotError(err).NotNil(s)
+	s = Workday(s, c)
+
+	times := []time.Time{
+		time.Date(2025, 1, 24, 10, 0, 0, 0, cst), // 周五
+		time.Date(2025, 1, 26, 9, 0, 0, 0, cst),  // 周日调休
+		time.Date(2025, 1, 27, 9, 0, 0, 0, cst),
+		time.Date(2025, 2, 5, 9, 0, 0, 0, cst), // 跳过春节
+		time.Date(2025, 2, 6, 9, 0, 0, 0, cst),
+		time.Date(2025, 2, 7, 9, 0, 0, 0, cst),
+		time.Date(2025, 2, 8, 9, 0, 0, 0, cst), // 周六调休
+		time.Date(2025, 2, 10, 9, 0, 0, 0, cst),
+	}
+	for i := 1; i < len(times); i++ {
+		a.Equal(s.Next(times[i-1]), times[i], "%d", i)
+	}
+
+	// 调休日当天，已经过了执行时间
+	a.Equal(s.Next(time.Date(2025, 1, 26, 10, 0, 0, 0, cst)), time.Date(2025, 1, 27, 9, 0, 0, 0, cst))
+
+	// 多个执行时间
+	s, err = cron.Parse("0 0 9,18 * * 1-5", cst)
+	a.NotError(err).NotNil(s)
+	s = Workday(s, c)
+	a.Equal(s.Next(time.Date(2025, 9, 28, 9, 0, 0, 0, cst)), time.Date(2025, 9, 28, 18, 0, 0, 0, cst)).
+		Equal(s.Next(time.Date(2025, 9, 30, 18, 0, 0, 0, cst)), time.Date(2025, 10, 9, 9, 0, 0, 0, cst)).
+		Equal(s.Next(time.Date(2025, 10, 10, 18, 0, 0, 0, cst)), time.Date(2025, 10, 11, 9, 0, 0, 0, cst))
+
+	// s 本身包含周末
+	s, err = cron.Parse("0 0 9 * * *", cst)
+	a.NotError(err).NotNil(s)
+	s = Workday(s, c)
+	a.Equal(s.Next(time.Date(2025, 4, 25, 10, 0, 0, 0, cst)), time.Date(2025, 4, 27, 9, 0, 0, 0, cst))
+}
+
+func TestHoliday(t *testing.T) {
+	a := assert.New(t, false)
+	c := loadCalendar(a)
+
+	s, err := cron.Parse("0 0 9 * * *", cst)
+	a.NotError(err).NotNil(s)
+	s = Holiday(s, c)
+
+	next := s.Next(time.Date(2025, 1, 1, 10, 0, 0, 0, cst))
+	a.Equal(next, time.Date(2025, 1, 28, 9, 0, 0, 0, cst))
+	next = s.Next(time.Date(2025, 2, 4, 10, 0, 0, 0, cst))
+	a.Equal(next, time.Date(2025, 4, 4, 9, 0, 0, 0, cst))
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package ics 从 iCalendar 文件中读取 VEVENT 作为调度器
+//
+// 仅处理 VEVENT 中的 UID、SUMMARY、DTSTART、RRULE、RDATE 和 EXDATE，
+// 其中 TZID 指定的时区从本地的时区数据库中加载，不会有任何网络请求。
+// 读取的 [Event] 可以直接作为 [schedulers.Scheduler] 使用：
+//
+//	events, err := ics.Read(r, time.Local)
+//	for _, e := range events {
+//	    srv.New(e.Title(), f, e, false)
+//	}
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+// Event 表示 iCalendar 中的 VEVENT
+type Event struct {
+	UID     string
+	Summary string
+	Set     *rrule.Set
+}
+
+// Title 以 SUMMARY 作为任务的标题
+func (e *Event) Title() localeutil.Stringer { return localeutil.StringPhrase(e.Summary) }
+
+func (e *Event) Next(last time.Time) time.Time { return e.Set.Next(last) }
+
+// Read 从 r 中读取所有的 VEVENT
+//
+// loc 表示未指定时区的时间所采用的时区。
+func Read(r io.Reader, loc *time.Location) ([]*Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*Event, 0, 10)
+	var e *Event
+	var rule []string // 组成 rrule.Set 的内容
+	var start int     // VEVENT 的起始行号
+
+	for i, line := range lines {
+		prop, _, value, found := rrule.SplitProperty(line)
+		if !found {
+			return nil, localeutil.Error("invalid ics content at line %d", i+1)
+		}
+
+		switch prop = strings.ToUpper(prop); {
+		case prop == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			if e != nil {
+				return nil, localeutil.Error("invalid ics content at line %d", i+1)
+			}
+			e = &Event{}
+			rule = rule[:0]
+			start = i + 1
+		case prop == "END" && strings.EqualFold(value, "VEVENT"):
+			if e == nil {
+				return nil, localeutil.Error("invalid ics content at line %d", i+1)
+			}
+
+			if len(rule) == 0 || !strings.HasPrefix(strings.ToUpper(rule[0]), "DTSTART") {
+				return nil, localeutil.Error("missing %s in VEVENT at line %d", "DTSTART", start)
+			}
+			if e.Set, err = rrule.ParseProperties(rule, loc); err != nil {
+				return nil, err
+			}
+
+			events = append(events, e)
+			e = nil
+		case e == nil: // 非 VEVENT 中的内容
+		case prop == "UID":
+			e.UID = unescape(value)
+		case prop == "SUMMARY":
+			e.Summary = unescape(value)
+		case prop == "DTSTART":
+			rule = append([]string{line}, rule...)
+		case prop == "RRULE" || prop == "RDATE" || prop == "EXDATE":
+			rule = append(rule, line)
+		}
+	}
+
+	if e != nil {
+		return nil, localeutil.Error("invalid ics content at line %d", start)
+	}
+	return events, nil
+}
+
+// 读取所有的行，并合并被折叠的行。
+func unfold(r io.Reader) ([]string, error) {
+	lines := make([]string, 0, 100)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		switch {
+		case line == "":
+		case (line[0] == ' ' || line[0] == '\t') && len(lines) > 0:
+			lines[len(lines)-1] += line[1:]
+		default:
+			lines = append(lines, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+
+func unescape(v string) string { return unescaper.Replace(v) }
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package ics
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var _ schedulers.Scheduler = &Event{}
+
+func TestRead(t *testing.T) {
+	a := assert.New(t, false)
+
+	f, err := os.Open("./testdata/maintenance.ics")
+	a.NotError(err).NotNil(f)
+	defer f.Close()
+
+	events, err := Read(f, time.UTC)
+	a.NotError(err).Length(events, 2)
+
+	sh, err := time.LoadLocation("Asia/Shanghai")
+	a.NotError(err)
+
+	e := events[0]
+	a.Equal(e.UID, "weekly-db@example.com").
+		Equal(e.Summary, "数据库维护, 每周一次").
+		Equal(e.Title(), localeutil.StringPhrase("数据库维护, 每周一次")).
+		Equal(e.Set.RRule.Count, 10).
+		Length(e.Set.ExDates, 2)
+
+	next := e.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 1, 5, 2, 0, 0, 0, sh))
+	next = e.Next(next)
+	a.Equal(next, time.Date(2026, 1, 26, 2, 0, 0, 0, sh))
+
+	e = events[1]
+	a.Equal(e.Summary, "Network switch upgrade").
+		Nil(e.Set.RRule)
+	next = e.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC))
+	a.True(e.Next(next).IsZero())
+}
+
+func TestRead_error(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, v := range []string{
+		"BEGIN:VEVENT\nSUMMARY:abc\nEND:VEVENT",                            // 缺少 DTSTART
+		"BEGIN:VEVENT\nDTSTART:20260101T000000Z\n",                         // 缺少 END
+		"BEGIN:VEVENT\nBEGIN:VEVENT\nDTSTART:20260101T000000Z\nEND:VEVENT", // 嵌套
+		"END:VEVENT",                        // 缺少 BEGIN
+		"BEGIN:VEVENT\nDTSTART\nEND:VEVENT", // 格式错误
+		"BEGIN:VEVENT\nDTSTART:20260101T000000Z\nRRULE:FREQ=NEVER\nEND:VEVENT", // RRULE 错误
+	} {
+		events, err := Read(strings.NewReader(v), time.UTC)
+		a.Error(err, v).Nil(events)
+	}
+
+	events, err := Read(strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), time.UTC)
+	a.NotError(err).Empty(events)
+
+	// 完整的 TZID 传递给了 time.LoadLocation
+	_, err = Read(strings.NewReader("BEGIN:VEVENT\nDTSTART;TZID=\"China Standard Time\":20260101T000000\nEND:VEVENT"), time.UTC)
+	a.ErrorString(err, "China Standard Time")
+}
+
+func TestRead_params(t *testing.T) {
+	a := assert.New(t, false)
+
+	sh, err := time.LoadLocation("Asia/Shanghai")
+	a.NotError(err)
+
+	text := "BEGIN:VEVENT\n" +
+		"SUMMARY;ALTREP=\"cid:part1;a b\":backup: daily\n" +
+		"DTSTART;X-LABEL=\"weekly; 10:00\";TZID=\"Asia/Shanghai\":20260105T020000\n" +
+		"RRULE:FREQ=DAILY;COUNT=2\n" +
+		"EXDATE;TZID=Asia/Shanghai;VALUE=\"DATE-TIME\":20260105T020000\n" +
+		"END:VEVENT"
+	events, err := Read(strings.NewReader(text), time.UTC)
+	a.NotError(err).Length(events, 1)
+
+	e := events[0]
+	a.Equal(e.Summary, "backup: daily").
+		Equal(e.Set.DTStart, time.Date(2026, 1, 5, 2, 0, 0, 0, sh)).
+		Equal(e.Next(e.Set.DTStart.Add(-time.Second)), time.Date(2026, 1, 6, 2, 0, 0, 0, sh))
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package interval 按日历间隔执行的定时器
+//
+// 与 [ticker] 添加固定的 [time.Duration] 不同，interval 以日、周或是月为单位，
+// 在指定时区中按日历计算下一次的执行时间，不会因为夏令时的切换而产生偏移。
+//
+// [ticker]: https://pkg.go.dev/github.com/issue9/scheduled/schedulers/ticker
+package interval
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// 跳过不存在的日期时，最多尝试的次数。
+const maxSkip = 1000
+
+// Unit 间隔的单位
+type Unit int8
+
+const (
+	Day Unit = iota
+	Week
+	Month
+)
+
+// Overflow 按月计算时，目标月份中不存在该日期时的处理方式
+type Overflow int8
+
+const (
+	Clamp Overflow = iota // 使用该月的最后一天
+	Skip                  // 跳过该月
+)
+
+// Interval 按日历间隔执行的定时器
+type Interval struct {
+	start    time.Time
+	n        int
+	unit     Unit
+	overflow Overflow
+}
+
+var _ schedulers.Scheduler = &Interval{}
+
+// New 声明 [Interval] 对象
+//
+// start 为起始时间，其日期、时间以及时区即为之后每次执行的基准，
+// start 本身也是第一次执行的时间；
+// n 表示每 n 个 u 执行一次，必须大于 0；
+// o 仅在 u 为 [Month] 时有效。
+func New(start time.Time, n int, u Unit, o Overflow) (*Interval, error) {
+	switch {
+	case n <= 0:
+		return nil, localeutil.Error("invalid value %s", strconv.Itoa(n))
+	case u < Day || u > Month:
+		return nil, localeutil.Error("invalid value %s", u.String())
+	case o < Clamp || o > Skip:
+		return nil, localeutil.Error("invalid value %s", strconv.Itoa(int(o)))
+	}
+
+	return &Interval{start: start, n: n, unit: u, overflow: o}, nil
+}
+
+// Days 每 n 天在 start 所在的时间执行一次
+func Days(start time.Time, n int) (*Interval, error) { return New(start, n, Day, Clamp) }
+
+// Weeks 每 n 周在 start 所在的星期及时间执行一次
+func Weeks(start time.Time, n int) (*Interval, error) { return New(start, n, Week, Clamp) }
+
+// Months 每 n 个月在 start 所在的日期及时间执行一次
+func Months(start time.Time, n int, o Overflow) (*Interval, error) {
+	return New(start, n, Month, o)
+}
+
+// at 返回第 k 次的执行时间
+//
+// 如果该次执行因为 [Skip] 被跳过，返回 false。
+func (i *Interval) at(k int) (time.Time, bool) {
+	y, m, d := i.start.Date()
+	h, mi, s := i.start.Clock()
+	ns := i.start.Nanosecond()
+	loc := i.start.Location()
+
+	switch i.unit {
+	case Day:
+		return time.Date(y, m, d+k*i.n, h, mi, s, ns, loc), true
+	case Week:
+		return time.Date(y, m, d+7*k*i.n, h, mi, s, ns, loc), true
+	default:
+		first := time.Date(y, m+time.Month(k*i.n), 1, h, mi, s, ns, loc)
+		if days := daysIn(first.Year(), first.Month()); d > days {
+			if i.overflow == Skip {
+				return time.Time{}, false
+			}
+			d = days
+		}
+		return time.Date(first.Year(), first.Month(), d, h, mi, s, ns, loc), true
+	}
+}
+
+// 估算 t 之前的执行次数，返回值可能偏大一次。
+func (i *Interval) estimate(t time.Time) int {
+	t = t.In(i.start.Location())
+	switch i.unit {
+	case Day, Week:
+		d := days(civil(i.start), civil(t))
+		if i.unit == Week {
+			d /= 7
+		}
+		return d / i.n
+	default:
+		months := (t.Year()-i.start.Year())*12 + int(t.Month()-i.start.Month())
+		return months / i.n
+	}
+}
+
+func (i *Interval) Next(last time.Time) time.Time {
+	k := 0
+	if !last.Before(i.start) {
+		k = max(i.estimate(last)-1, 0)
+	}
+
+	for skip := 0; skip < maxSkip; k++ {
+		next, ok := i.at(k)
+		if !ok {
+			skip++
+			continue
+		}
+		if next.After(last) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (u Unit) String() string {
+	switch u {
+	case Day:
+		return "day"
+	case Week:
+		return "week"
+	case Month:
+		return "month"
+	default:
+		return "<unknown>"
+	}
+}
+
+// 以 UTC 表示 t 的本地日期
+func civil(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
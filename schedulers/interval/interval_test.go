@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package interval
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+	start := time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC)
+
+	i, err := New(start, 0, Day, Clamp)
+	a.Error(err).Nil(i)
+
+	i, err = New(start, 1, Month+1, Clamp)
+	a.Error(err).Nil(i)
+
+	i, err = New(start, 1, Month, Skip+1)
+	a.Error(err).Nil(i)
+
+	i, err = New(start, 2, Month, Skip)
+	a.NotError(err).NotNil(i)
+}
+
+func TestInterval_Next(t *testing.T) {
+	a := assert.New(t, false)
+
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+
+	// 跨越夏令时，本地时间保持不变
+	i, err := Days(time.Date(2026, 3, 6, 9, 0, 0, 0, ny), 1)
+	a.NotError(err).NotNil(i)
+	last := time.Date(2026, 3, 1, 0, 0, 0, 0, ny)
+	for _, day := range []int{6, 7, 8, 9} {
+		last = i.Next(last)
+		a.Equal(last, time.Date(2026, 3, day, 9, 0, 0, 0, ny))
+	}
+	a.Equal(i.Next(time.Date(2026, 11, 1, 12, 0, 0, 0, ny)), time.Date(2026, 11, 2, 9, 0, 0, 0, ny))
+
+	// 每 3 天
+	i, err = Days(time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC), 3)
+	a.NotError(err).NotNil(i)
+	a.Equal(i.Next(time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)), time.Date(2026, 1, 4, 8, 0, 0, 0, time.UTC)).
+		Equal(i.Next(time.Date(2026, 1, 4, 7, 0, 0, 0, time.UTC)), time.Date(2026, 1, 4, 8, 0, 0, 0, time.UTC)).
+		Equal(i.Next(time.Date(2026, 12, 31, 8, 0, 0, 0, time.UTC)), time.Date(2027, 1, 2, 8, 0, 0, 0, time.UTC))
+
+	// 每 2 周
+	i, err = Weeks(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), 2)
+	a.NotError(err).NotNil(i)
+	a.Equal(i.Next(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)), time.Date(2026, 1, 19, 10, 0, 0, 0, time.UTC)).
+		Equal(i.Next(time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC)), time.Date(2026, 1, 19, 10, 0, 0, 0, time.UTC)).
+		Equal(i.Next(time.Date(2026, 1, 19, 10, 0, 0, 0, time.UTC)), time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC))
+}
+
+func TestInterval_Next_month(t *testing.T) {
+	a := assert.New(t, false)
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	i, err := Months(start, 1, Clamp)
+	a.NotError(err).NotNil(i)
+	last := start.Add(-time.Hour)
+	for _, d := range []time.Time{
+		time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 4, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 5, 31, 9, 0, 0, 0, time.UTC),
+	} {
+		last = i.Next(last)
+		a.Equal(last, d)
+	}
+
+	// 每两个月的 31 日
+	i, err = Months(start, 2, Skip)
+	a.NotError(err).NotNil(i)
+	last = start
+	for _, d := range []time.Time{
+		time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 5, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 7, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC), // 跳过 9、11 月
+	} {
+		last = i.Next(last)
+		a.Equal(last, d)
+	}
+
+	// 闰年的 2 月 29 日
+	i, err = Months(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 12, Skip)
+	a.NotError(err).NotNil(i)
+	a.Equal(i.Next(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC))
+
+	i, err = Months(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 12, Clamp)
+	a.NotError(err).NotNil(i)
+	a.Equal(i.Next(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC))
+}
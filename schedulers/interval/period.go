@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package interval
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Period 仅保留 s 中从 anchor 开始每 n 个周期中第一个周期内的时间点
+//
+// 周期的划分以 anchor 所在的时区为准：
+// [Day] 以自然日计算；[Week] 以 ISO 8601 中周一开始的自然周计算；
+// [Month] 以自然月计算。anchor 之前的时间点同样按此规则向前推算。
+//
+// 比如每隔一周的周一：
+//
+//	s, err := cron.Parse("0 0 10 * * 1", loc)
+//	p, err := interval.Period(s, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), 2, interval.Week)
+func Period(s schedulers.Scheduler, anchor time.Time, n int, u Unit) (schedulers.Scheduler, error) {
+	switch {
+	case n <= 0:
+		return nil, localeutil.Error("invalid value %s", strconv.Itoa(n))
+	case u < Day || u > Month:
+		return nil, localeutil.Error("invalid value %s", u.String())
+	}
+
+	return schedulers.FilterDays(s, anchor.Location(), func(t time.Time) bool {
+		return mod(periods(anchor, t, u), n) == 0
+	}), nil
+}
+
+// ISOWeeks 仅保留 s 中 ISO 8601 周数在 weeks 中的时间点
+//
+// 周数以 loc 时区计算，为 nil 表示使用 [time.Local]。
+// 比如 ISOWeeks(s, loc, 1, 3, 5, ..., 53) 表示仅在单周执行。
+// 需要注意的是，有 53 周的年份，其第 53 周与下一年的第 1 周是连续的两个单周，
+// 如果需要严格的隔周执行，应该使用 [Period]。
+func ISOWeeks(s schedulers.Scheduler, loc *time.Location, weeks ...int) schedulers.Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	return schedulers.FilterDays(s, loc, func(t time.Time) bool {
+		_, w := ISOWeek(t, loc)
+		return slices.Contains(weeks, w)
+	})
+}
+
+// ISOWeek 返回 t 在 loc 时区中的 ISO 8601 年份和周数
+//
+// loc 为 nil 表示使用 [time.Local]。
+func ISOWeek(t time.Time, loc *time.Location) (year, week int) {
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).ISOWeek()
+}
+
+// 返回从 anchor 到 t 之间间隔的周期数，t 在 anchor 之前时返回负数。
+func periods(anchor, t time.Time, u Unit) int {
+	t = t.In(anchor.Location())
+
+	switch u {
+	case Day:
+		return days(civil(anchor), civil(t))
+	case Week:
+		return days(monday(anchor), monday(t)) / 7
+	default:
+		return (t.Year()-anchor.Year())*12 + int(t.Month()-anchor.Month())
+	}
+}
+
+// 以 UTC 表示 t 所在周的周一
+func monday(t time.Time) time.Time {
+	c := civil(t)
+	return c.AddDate(0, 0, -(int(c.Weekday())+6)%7)
+}
+
+func days(from, to time.Time) int { return int(to.Sub(from).Hours() / 24) }
+
+func mod(a, n int) int {
+	if a %= n; a < 0 {
+		a += n
+	}
+	return a
+}
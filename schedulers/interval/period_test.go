@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package interval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+func TestPeriod(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	s, err := cron.Parse("0 0 10 * * 1", loc)
+	a.NotError(err).NotNil(s)
+
+	p, err := Period(s, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), 0, Week)
+	a.Error(err).Nil(p)
+
+	p, err = Period(s, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), 2, Month+1)
+	a.Error(err).Nil(p)
+
+	// 隔周的周一，跨越年份
+	p, err = Period(s, time.Date(2026, 1, 7, 0, 0, 0, 0, loc), 2, Week)
+	a.NotError(err).NotNil(p)
+	last := time.Date(2025, 12, 1, 0, 0, 0, 0, loc)
+	for _, d := range []time.Time{
+		time.Date(2025, 12, 8, 10, 0, 0, 0, loc),
+		time.Date(2025, 12, 22, 10, 0, 0, 0, loc),
+		time.Date(2026, 1, 5, 10, 0, 0, 0, loc),
+		time.Date(2026, 1, 19, 10, 0, 0, 0, loc),
+		time.Date(2026, 2, 2, 10, 0, 0, 0, loc),
+	} {
+		last = p.Next(last)
+		a.Equal(last, d)
+	}
+
+	// 2026 有 53 周，跨年之后依然保持隔周
+	a.Equal(p.Next(time.Date(2026, 12, 15, 0, 0, 0, 0, loc)), time.Date(2026, 12, 21, 10, 0, 0, 0, loc)).
+		Equal(p.Next(time.Date(2026, 12, 21, 10, 0, 0, 0, loc)), time.Date(2027, 1, 4, 10, 0, 0, 0, loc)).
+		Equal(p.Next(time.Date(2027, 1, 4, 10, 0, 0, 0, loc)), time.Date(2027, 1, 18, 10, 0, 0, 0, loc))
+
+	// 每三天
+	s, err = cron.Parse("0 0 8 * * *", loc)
+	a.NotError(err).NotNil(s)
+	p, err = Period(s, time.Date(2026, 1, 1, 0, 0, 0, 0, loc), 3, Day)
+	a.NotError(err).NotNil(p)
+	a.Equal(p.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, loc)), time.Date(2026, 1, 4, 8, 0, 0, 0, loc)).
+		Equal(p.Next(time.Date(2025, 12, 25, 9, 0, 0, 0, loc)), time.Date(2025, 12, 26, 8, 0, 0, 0, loc))
+
+	// 每季度的第一个月
+	s, err = cron.Parse("0 0 8 1 * *", loc)
+	a.NotError(err).NotNil(s)
+	p, err = Period(s, time.Date(2026, 1, 15, 0, 0, 0, 0, loc), 3, Month)
+	a.NotError(err).NotNil(p)
+	a.Equal(p.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, loc)), time.Date(2026, 4, 1, 8, 0, 0, 0, loc)).
+		Equal(p.Next(time.Date(2025, 9, 1, 9, 0, 0, 0, loc)), time.Date(2025, 10, 1, 8, 0, 0, 0, loc))
+}
+
+func TestISOWeeks(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	y, w := ISOWeek(time.Date(2027, 1, 1, 0, 0, 0, 0, loc), loc)
+	a.Equal(y, 2026).Equal(w, 53)
+	y, w = ISOWeek(time.Date(2026, 12, 31, 20, 0, 0, 0, time.UTC), loc)
+	a.Equal(y, 2026).Equal(w, 53)
+	y, w = ISOWeek(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+	a.Equal(y, 2026).Equal(w, 1)
+
+	odd := make([]int, 0, 27)
+	for i := 1; i <= 53; i += 2 {
+		odd = append(odd, i)
+	}
+
+	var s schedulers.Scheduler
+	s, err := cron.Parse("0 0 10 * * 1", loc)
+	a.NotError(err).NotNil(s)
+	s = ISOWeeks(s, loc, odd...)
+	last := time.Date(2026, 12, 1, 0, 0, 0, 0, loc)
+	for _, d := range []time.Time{
+		time.Date(2026, 12, 14, 10, 0, 0, 0, loc), // 51
+		time.Date(2026, 12, 28, 10, 0, 0, 0, loc), // 53
+		time.Date(2027, 1, 4, 10, 0, 0, 0, loc),   // 1
+		time.Date(2027, 1, 18, 10, 0, 0, 0, loc),  // 3
+	} {
+		last = s.Next(last)
+		a.Equal(last, d)
+	}
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package lunar 按农历日期执行的定时器
+//
+// 内置了 1900 至 2100 年的农历数据，超出此范围的日期将被视为调度已经终结。
+package lunar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Date 农历日期
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+	Leap  bool // 是否为闰月
+}
+
+// Lunar 在每年的某个农历日期执行的定时器
+type Lunar struct {
+	month, day           int
+	leap                 bool
+	hour, minute, second int
+	loc                  *time.Location
+}
+
+var _ schedulers.Scheduler = &Lunar{}
+
+// FromTime 将公历日期转换为农历日期
+//
+// 仅使用 t 在其时区中的年月日部分。
+func FromTime(t time.Time) (Date, error) {
+	y, m, d := t.Date()
+	days := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Sub(base).Hours() / 24)
+	if days < 0 {
+		return Date{}, localeutil.Error("invalid date %s", t.Format(time.DateOnly))
+	}
+
+	year := minYear
+	for ; year <= maxYear; year++ {
+		n := yearDays(year)
+		if days < n {
+			break
+		}
+		days -= n
+	}
+	if year > maxYear {
+		return Date{}, localeutil.Error("invalid date %s", t.Format(time.DateOnly))
+	}
+
+	leap := leapMonth(year)
+	for month := 1; month <= 12; month++ {
+		n := monthDays(year, month)
+		if days < n {
+			return Date{Year: year, Month: month, Day: days + 1}, nil
+		}
+		days -= n
+
+		if month == leap {
+			if n = leapDays(year); days < n {
+				return Date{Year: year, Month: month, Day: days + 1, Leap: true}, nil
+			}
+			days -= n
+		}
+	}
+
+	panic("unreachable") // yearDays 已经保证了不会到达此处
+}
+
+// Time 转换为公历日期
+//
+// 返回 loc 时区中该日期的零点，loc 为 nil 表示 [time.Local]。
+func (d Date) Time(loc *time.Location) (time.Time, error) {
+	if err := d.valid(); err != nil {
+		return time.Time{}, err
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	days := d.Day - 1
+	for y := minYear; y < d.Year; y++ {
+		days += yearDays(y)
+	}
+
+	leap := leapMonth(d.Year)
+	for m := 1; m < d.Month; m++ {
+		days += monthDays(d.Year, m)
+		if m == leap {
+			days += leapDays(d.Year)
+		}
+	}
+	if d.Leap { // 闰月在同名的月份之后
+		days += monthDays(d.Year, d.Month)
+	}
+
+	t := base.AddDate(0, 0, days)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), nil
+}
+
+func (d Date) valid() error {
+	switch {
+	case d.Year < minYear || d.Year > maxYear:
+		return localeutil.Error("the value %d out of range [%d,%d]", d.Year, minYear, maxYear)
+	case d.Month < 1 || d.Month > 12:
+		return localeutil.Error("the value %d out of range [%d,%d]", d.Month, 1, 12)
+	case d.Leap && leapMonth(d.Year) != d.Month:
+		return localeutil.Error("invalid date %s", d.String())
+	case d.Day < 1 || d.Day > d.days():
+		return localeutil.Error("the value %d out of range [%d,%d]", d.Day, 1, d.days())
+	}
+	return nil
+}
+
+// 当前月份的天数
+func (d Date) days() int {
+	if d.Leap {
+		return leapDays(d.Year)
+	}
+	return monthDays(d.Year, d.Month)
+}
+
+// String 返回 2006-01-02 格式的内容，闰月会在月份之前加上 L，比如 2025-L06-01。
+func (d Date) String() string {
+	leap := ""
+	if d.Leap {
+		leap = "L"
+	}
+	return fmt.Sprintf("%04d-%s%02d-%02d", d.Year, leap, d.Month, d.Day)
+}
+
+// New 声明 [Lunar] 对象
+//
+// month 和 day 为农历的月和日，leap 表示是否为闰月；
+// 如果 leap 为 true，那么只在存在该闰月的年份执行；
+// 如果 day 为 30 而该月只有 29 天，则在该月的最后一天执行，比如除夕可以指定为 12 月 30 日；
+// hour、minute 和 second 为执行的时间；loc 为时区，为 nil 表示 [time.Local]。
+func New(month, day int, leap bool, hour, minute, second int, loc *time.Location) (*Lunar, error) {
+	switch {
+	case month < 1 || month > 12:
+		return nil, localeutil.Error("the value %d out of range [%d,%d]", month, 1, 12)
+	case day < 1 || day > 30:
+		return nil, localeutil.Error("the value %d out of range [%d,%d]", day, 1, 30)
+	case hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59:
+		return nil, localeutil.Error("invalid time %s", fmt.Sprintf("%02d:%02d:%02d", hour, minute, second))
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &Lunar{
+		month:  month,
+		day:    day,
+		leap:   leap,
+		hour:   hour,
+		minute: minute,
+		second: second,
+		loc:    loc,
+	}, nil
+}
+
+func (l *Lunar) Next(last time.Time) time.Time {
+	year := minYear
+	if d, err := FromTime(last.In(l.loc)); err == nil {
+		year = d.Year
+	} else if last.After(base) { // 超出了最大年份
+		return time.Time{}
+	}
+
+	for ; year <= maxYear; year++ {
+		if t, ok := l.at(year); ok && t.After(last) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// 返回农历 year 年的执行时间，如果该年份没有指定的闰月，返回 false。
+func (l *Lunar) at(year int) (time.Time, bool) {
+	d := Date{Year: year, Month: l.month, Day: l.day, Leap: l.leap}
+	if d.Leap && leapMonth(year) != d.Month {
+		return time.Time{}, false
+	}
+	d.Day = min(d.Day, d.days())
+
+	t, err := d.Time(l.loc)
+	if err != nil { // 已经验证过，不可能出错。
+		panic(err)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), l.hour, l.minute, l.second, 0, l.loc), true
+}
+
+// String 返回 M-D hh:mm:ss 格式的内容，闰月会在月份之前加上 L。
+func (l *Lunar) String() string {
+	leap := ""
+	if l.leap {
+		leap = "L"
+	}
+	return fmt.Sprintf("%s%d-%d %02d:%02d:%02d", leap, l.month, l.day, l.hour, l.minute, l.second)
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package lunar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 春节的公历日期
+var springFestivals = map[int]string{
+	1901: "1901-02-19",
+	1912: "1912-02-18",
+	1949: "1949-01-29",
+	1950: "1950-02-17",
+	1970: "1970-02-06",
+	1980: "1980-02-16",
+	1990: "1990-01-27",
+	2000: "2000-02-05",
+	2001: "2001-01-24",
+	2002: "2002-02-12",
+	2003: "2003-02-01",
+	2004: "2004-01-22",
+	2005: "2005-02-09",
+	2006: "2006-01-29",
+	2007: "2007-02-18",
+	2008: "2008-02-07",
+	2009: "2009-01-26",
+	2010: "2010-02-14",
+	2011: "2011-02-03",
+	2012: "2012-01-23",
+	2013: "2013-02-10",
+	2014: "2014-01-31",
+	2015: "2015-02-19",
+	2016: "2016-02-08",
+	2017: "2017-01-28",
+	2018: "2018-02-16",
+	2019: "2019-02-05",
+	2020: "2020-01-25",
+	2021: "2021-02-12",
+	2022: "2022-02-01",
+	2023: "2023-01-22",
+	2024: "2024-02-10",
+	2025: "2025-01-29",
+	2026: "2026-02-17",
+	2027: "2027-02-06",
+	2028: "2028-01-26",
+	2029: "2029-02-13",
+	2030: "2030-02-03",
+}
+
+func TestDate_Time(t *testing.T) {
+	a := assert.New(t, false)
+
+	for year, want := range springFestivals {
+		got, err := Date{Year: year, Month: 1, Day: 1}.Time(time.UTC)
+		a.NotError(err).Equal(got.Format(time.DateOnly), want)
+
+		d, err := FromTime(got)
+		a.NotError(err).Equal(d, Date{Year: year, Month: 1, Day: 1})
+	}
+
+	data := map[Date]string{
+		{Year: 2026, Month: 8, Day: 15}:             "2026-09-25", // 中秋
+		{Year: 2025, Month: 5, Day: 5}:              "2025-05-31", // 端午
+		{Year: 2025, Month: 6, Day: 1}:              "2025-06-25",
+		{Year: 2025, Month: 6, Day: 1, Leap: true}:  "2025-07-25", // 闰六月
+		{Year: 2025, Month: 7, Day: 1}:              "2025-08-23",
+		{Year: 2023, Month: 2, Day: 1, Leap: true}:  "2023-03-22", // 闰二月
+		{Year: 2024, Month: 12, Day: 29}:            "2025-01-28", // 除夕
+		{Year: 1900, Month: 1, Day: 1}:              "1900-01-31",
+		{Year: 2033, Month: 11, Day: 1, Leap: true}: "2033-12-22",
+	}
+	loc := time.FixedZone("UTC+8", 8*3600)
+	for d, want := range data {
+		got, err := d.Time(loc)
+		a.NotError(err, d).
+			Equal(got.Format(time.DateOnly), want, d).
+			Equal(got.Location(), loc)
+
+		back, err := FromTime(got)
+		a.NotError(err).Equal(back, d)
+	}
+
+	for _, d := range []Date{
+		{Year: 1899, Month: 1, Day: 1},
+		{Year: 2101, Month: 1, Day: 1},
+		{Year: 2025, Month: 13, Day: 1},
+		{Year: 2025, Month: 5, Day: 1, Leap: true},
+		{Year: 2025, Month: 6, Day: 30, Leap: true},
+		{Year: 2025, Month: 6, Day: 0},
+	} {
+		got, err := d.Time(nil)
+		a.Error(err, d).True(got.IsZero())
+	}
+
+	_, err := FromTime(time.Date(1900, 1, 30, 0, 0, 0, 0, time.UTC))
+	a.Error(err)
+	_, err = FromTime(time.Date(2200, 1, 30, 0, 0, 0, 0, time.UTC))
+	a.Error(err)
+
+	a.Equal(Date{Year: 2025, Month: 6, Day: 1, Leap: true}.String(), "2025-L06-01").
+		Equal(Date{Year: 2025, Month: 6, Day: 1}.String(), "2025-06-01")
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	l, err := New(13, 1, false, 0, 0, 0, nil)
+	a.Error(err).Nil(l)
+	l, err = New(1, 31, false, 0, 0, 0, nil)
+	a.Error(err).Nil(l)
+	l, err = New(1, 1, false, 24, 0, 0, nil)
+	a.Error(err).Nil(l)
+
+	l, err = New(1, 1, false, 0, 0, 0, nil)
+	a.NotError(err).NotNil(l).
+		Equal(l.loc, time.Local).
+		Equal(l.String(), "1-1 00:00:00")
+
+	l, err = New(6, 1, true, 9, 30, 0, nil)
+	a.NotError(err).NotNil(l).
+		Equal(l.String(), "L6-1 09:30:00")
+}
+
+func TestLunar_Next(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	// 春节 9 点
+	l, err := New(1, 1, false, 9, 0, 0, loc)
+	a.NotError(err).NotNil(l)
+	last := time.Date(2025, 10, 1, 0, 0, 0, 0, loc)
+	for _, year := range []int{2026, 2027, 2028} {
+		last = l.Next(last)
+		want, err := time.ParseInLocation(time.DateOnly, springFestivals[year], loc)
+		a.NotError(err).Equal(last, want.Add(9*time.Hour))
+	}
+	a.Equal(l.Next(time.Date(2026, 2, 17, 8, 0, 0, 0, loc)), time.Date(2026, 2, 17, 9, 0, 0, 0, loc))
+
+	// 除夕，2025 年的十二月只有 29 天
+	l, err = New(12, 30, false, 20, 0, 0, loc)
+	a.NotError(err).NotNil(l)
+	a.Equal(l.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)), time.Date(2026, 2, 16, 20, 0, 0, 0, loc))
+
+	// 闰六月，跳过没有闰六月的年份
+	l, err = New(6, 1, true, 0, 0, 0, loc)
+	a.NotError(err).NotNil(l)
+	next := l.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, loc))
+	a.Equal(next, time.Date(2025, 7, 25, 0, 0, 0, 0, loc))
+	next = l.Next(next)
+	d, err := FromTime(next)
+	a.NotError(err).Equal(d.Month, 6).True(d.Leap).True(d.Year > 2025)
+
+	// 超出范围
+	l, err = New(1, 1, false, 0, 0, 0, loc)
+	a.NotError(err).NotNil(l)
+	a.Equal(l.Next(time.Date(1800, 1, 1, 0, 0, 0, 0, loc)), time.Date(1900, 1, 31, 0, 0, 0, 0, loc))
+	a.True(l.Next(time.Date(2100, 3, 1, 0, 0, 0, 0, loc)).IsZero())
+	a.True(l.Next(time.Date(2200, 1, 1, 0, 0, 0, 0, loc)).IsZero())
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package lunar
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestTable(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Length(lunarInfo, maxYear-minYear+1)
+
+	a.Equal(leapMonth(2023), 2).
+		Equal(leapMonth(2025), 6).
+		Equal(leapMonth(2026), 0).
+		Equal(leapMonth(2033), 11).
+		Equal(leapDays(2026), 0).
+		Equal(leapDays(2025), 29)
+
+	for y := minYear; y <= maxYear; y++ {
+		days := yearDays(y)
+		if leapMonth(y) == 0 {
+			a.True(days >= 353 && days <= 355, "%d:%d", y, days)
+		} else {
+			a.True(days >= 383 && days <= 385, "%d:%d", y, days)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package natural
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+var enWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sundays": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mondays": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tuesdays": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wednesdays": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thursdays": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fridays": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "saturdays": time.Saturday, "sat": time.Saturday,
+}
+
+var enMonths = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var enOrdinals = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1,
+}
+
+var enUnits = map[string]rrule.Frequency{
+	"minute": rrule.Minutely, "minutes": rrule.Minutely,
+	"hour": rrule.Hourly, "hours": rrule.Hourly,
+	"day": rrule.Daily, "days": rrule.Daily,
+	"week": rrule.Weekly, "weeks": rrule.Weekly,
+	"month": rrule.Monthly, "months": rrule.Monthly,
+	"year": rrule.Yearly, "years": rrule.Yearly,
+}
+
+type enParser struct {
+	tokens []string
+	pos    int
+	s      *schedule
+}
+
+func parseEN(text string) (*schedule, error) {
+	p := &enParser{
+		tokens: strings.FieldsFunc(strings.ToLower(strings.ReplaceAll(text, ",", " , ")), unicode.IsSpace),
+		s:      &schedule{interval: 1},
+	}
+
+	if p.accept("at") {
+		if err := p.parseTimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.parseBody(); err != nil {
+		return nil, err
+	}
+
+	if p.accept("at") {
+		if len(p.s.times) > 0 {
+			return nil, p.fail()
+		}
+		if err := p.parseTimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, p.fail()
+	}
+	if len(p.s.times) > 0 && (p.s.freq == rrule.Minutely || p.s.freq == rrule.Hourly) {
+		return nil, timeWithFrequency()
+	}
+	return p.s, nil
+}
+
+func (p *enParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *enParser) accept(words ...string) bool {
+	for _, w := range words {
+		if p.peek() == w {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// 返回从当前位置开始的所有内容
+func (p *enParser) fail() error {
+	return notUnderstood(strings.Join(p.tokens[min(p.pos, len(p.tokens)):], " "))
+}
+
+// 列表的分隔符
+func (p *enParser) separator() bool {
+	if p.accept(",") {
+		p.accept("and")
+		return true
+	}
+	return p.accept("and")
+}
+
+func (p *enParser) parseBody() error {
+	switch {
+	case p.accept("daily"):
+		p.s.freq = rrule.Daily
+		return nil
+	case p.accept("hourly"):
+		p.s.freq = rrule.Hourly
+		return nil
+	case p.accept("every", "each"):
+	}
+
+	word := p.peek()
+	if n, err := strconv.Atoi(word); err == nil && n > 0 {
+		p.pos++
+		p.s.interval = n
+		return p.parseUnit()
+	}
+
+	if _, found := enWeekdays[word]; found {
+		p.s.freq = rrule.Weekly
+		return p.parseWeekdays()
+	}
+
+	p.pos++
+	switch word {
+	case "other":
+		p.s.interval = 2
+		return p.parseUnit()
+	case "weekday", "weekdays", "workday", "workdays":
+		p.s.freq = rrule.Weekly
+		p.s.byDay = workdays
+		return nil
+	case "weekend", "weekends":
+		p.s.freq = rrule.Weekly
+		p.s.byDay = weekend
+		return nil
+	case "last":
+		if p.accept("day") {
+			p.s.byMonthDay = []int{-1}
+			return p.parseOfMonth()
+		}
+	}
+
+	if n, found := enOrdinals[word]; found { // first monday of the month
+		day, found := enWeekdays[p.peek()]
+		if !found {
+			return p.fail()
+		}
+		p.pos++
+		p.s.byDay = []rrule.Weekday{{N: n, Day: day}}
+		return p.parseOfMonth()
+	}
+
+	p.pos--
+	return p.parseUnit()
+}
+
+// of the month
+func (p *enParser) parseOfMonth() error {
+	if !p.accept("of") {
+		return p.fail()
+	}
+	p.accept("the", "every", "each")
+	if !p.accept("month") {
+		return p.fail()
+	}
+	p.s.freq = rrule.Monthly
+	return nil
+}
+
+// 时间单位以及之后的 on 子句
+func (p *enParser) parseUnit() error {
+	freq, found := enUnits[p.peek()]
+	if !found {
+		return p.fail()
+	}
+	p.pos++
+	p.s.freq = freq
+
+	switch freq {
+	case rrule.Weekly:
+		if p.accept("on") {
+			return p.parseWeekdays()
+		}
+		p.s.byDay = weekdays(time.Monday)
+	case rrule.Monthly:
+		if p.accept("on") {
+			p.accept("the")
+			return p.parseMonthDays()
+		}
+		p.s.byMonthDay = []int{1}
+	case rrule.Yearly:
+		if p.accept("on") {
+			return p.parseYearDay()
+		}
+		p.s.byMonth = []int{1}
+		p.s.byMonthDay = []int{1}
+	}
+	return nil
+}
+
+// monday, wednesday and friday
+func (p *enParser) parseWeekdays() error {
+	for {
+		day, found := enWeekdays[p.peek()]
+		if !found {
+			return p.fail()
+		}
+		p.pos++
+		p.s.byDay = append(p.s.byDay, rrule.Weekday{Day: day})
+
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+// 1st and 15th 或是 last day
+func (p *enParser) parseMonthDays() error {
+	for {
+		if p.accept("last") {
+			if !p.accept("day") {
+				return p.fail()
+			}
+			p.s.byMonthDay = append(p.s.byMonthDay, -1)
+		} else {
+			day, ok := enDay(p.peek())
+			if !ok {
+				return p.fail()
+			}
+			p.pos++
+			p.s.byMonthDay = append(p.s.byMonthDay, day)
+		}
+
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+// january 1st
+func (p *enParser) parseYearDay() error {
+	month, found := enMonths[p.peek()]
+	if !found {
+		return p.fail()
+	}
+	p.pos++
+
+	day, ok := enDay(p.peek())
+	if !ok || day > time.Date(2000, month+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return p.fail()
+	}
+	p.pos++
+
+	p.s.byMonth = []int{int(month)}
+	p.s.byMonthDay = []int{day}
+	return nil
+}
+
+func (p *enParser) parseTimes() error {
+	for {
+		if err := p.parseTime(); err != nil {
+			return err
+		}
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+// 9am、9 am、9:30pm、17:00、noon 和 midnight
+func (p *enParser) parseTime() error {
+	word := p.peek()
+	switch word {
+	case "noon":
+		p.pos++
+		return p.s.addTime(12, 0)
+	case "midnight":
+		p.pos++
+		return p.s.addTime(0, 0)
+	}
+
+	suffix := ""
+	if s, found := strings.CutSuffix(word, "am"); found {
+		word, suffix = s, "am"
+	} else if s, found := strings.CutSuffix(word, "pm"); found {
+		word, suffix = s, "pm"
+	}
+
+	h, m, hasMinute := strings.Cut(word, ":")
+	hour, err := strconv.Atoi(h)
+	if err != nil || h == "" {
+		return p.fail()
+	}
+	minute := 0
+	if hasMinute {
+		if minute, err = strconv.Atoi(m); err != nil || len(m) != 2 {
+			return p.fail()
+		}
+	}
+	p.pos++
+
+	if suffix == "" && p.accept("am") {
+		suffix = "am"
+	} else if suffix == "" && p.accept("pm") {
+		suffix = "pm"
+	}
+
+	switch {
+	case suffix != "" && (hour < 1 || hour > 12):
+		p.pos--
+		return p.fail()
+	case suffix == "am" && hour == 12:
+		hour = 0
+	case suffix == "pm" && hour != 12:
+		hour += 12
+	case suffix == "" && !hasMinute: // 单独的数字必须带 am 或是 pm
+		p.pos--
+		return p.fail()
+	}
+
+	return p.s.addTime(hour, minute)
+}
+
+// 1、1st、2nd、3rd、4th
+func enDay(word string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if s, found := strings.CutSuffix(word, suffix); found {
+			word = s
+			break
+		}
+	}
+
+	day, err := strconv.Atoi(word)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
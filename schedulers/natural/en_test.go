@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package natural
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+func TestParseEN(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]*schedule{
+		"every day":                {freq: rrule.Daily, interval: 1},
+		"daily":                    {freq: rrule.Daily, interval: 1},
+		"Every Day at 9AM":         {freq: rrule.Daily, interval: 1, times: []clock{{9, 0}}},
+		"at 9 am every day":        {freq: rrule.Daily, interval: 1, times: []clock{{9, 0}}},
+		"every day at noon":        {freq: rrule.Daily, interval: 1, times: []clock{{12, 0}}},
+		"each day at midnight":     {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}}},
+		"every day at 12am, 12pm":  {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}, {12, 0}}},
+		"every day at 9:30pm":      {freq: rrule.Daily, interval: 1, times: []clock{{21, 30}}},
+		"every day at 17:05":       {freq: rrule.Daily, interval: 1, times: []clock{{17, 5}}},
+		"every hour":               {freq: rrule.Hourly, interval: 1},
+		"hourly":                   {freq: rrule.Hourly, interval: 1},
+		"every minute":             {freq: rrule.Minutely, interval: 1},
+		"every 15 minutes":         {freq: rrule.Minutely, interval: 15},
+		"every 2 hours":            {freq: rrule.Hourly, interval: 2},
+		"every 3 days at 8am":      {freq: rrule.Daily, interval: 3, times: []clock{{8, 0}}},
+		"every other day":          {freq: rrule.Daily, interval: 2},
+		"every weekday at 9am":     {freq: rrule.Weekly, interval: 1, byDay: workdays, times: []clock{{9, 0}}},
+		"every weekend":            {freq: rrule.Weekly, interval: 1, byDay: weekend},
+		"every monday at 10:30":    {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday), times: []clock{{10, 30}}},
+		"mondays and fridays":      {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday, time.Friday)},
+		"every mon, wed and fri":   {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday, time.Wednesday, time.Friday)},
+		"every week":               {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday)},
+		"every 2 weeks on tuesday": {freq: rrule.Weekly, interval: 2, byDay: weekdays(time.Tuesday)},
+		"every other week on sat":  {freq: rrule.Weekly, interval: 2, byDay: weekdays(time.Saturday)},
+		"every month":              {freq: rrule.Monthly, interval: 1, byMonthDay: []int{1}},
+		"every month on the 15th":  {freq: rrule.Monthly, interval: 1, byMonthDay: []int{15}},
+		"every month on the 1st and 15th at 8am": {
+			freq: rrule.Monthly, interval: 1, byMonthDay: []int{1, 15}, times: []clock{{8, 0}},
+		},
+		"every month on the last day":        {freq: rrule.Monthly, interval: 1, byMonthDay: []int{-1}},
+		"last day of the month at 11pm":      {freq: rrule.Monthly, interval: 1, byMonthDay: []int{-1}, times: []clock{{23, 0}}},
+		"first monday of the month at noon":  {freq: rrule.Monthly, interval: 1, byDay: []rrule.Weekday{{N: 1, Day: time.Monday}}, times: []clock{{12, 0}}},
+		"every last friday of every month":   {freq: rrule.Monthly, interval: 1, byDay: []rrule.Weekday{{N: -1, Day: time.Friday}}},
+		"third wednesday of month":           {freq: rrule.Monthly, interval: 1, byDay: []rrule.Weekday{{N: 3, Day: time.Wednesday}}},
+		"every year":                         {freq: rrule.Yearly, interval: 1, byMonth: []int{1}, byMonthDay: []int{1}},
+		"every year on december 25th at 8am": {freq: rrule.Yearly, interval: 1, byMonth: []int{12}, byMonthDay: []int{25}, times: []clock{{8, 0}}},
+		"every 3 months on the 1st":          {freq: rrule.Monthly, interval: 3, byMonthDay: []int{1}},
+	}
+	for text, want := range data {
+		s, err := parseEN(text)
+		a.NotError(err, text).Equal(s, want, text)
+	}
+
+	errs := map[string]string{
+		"":                          "incomplete schedule",
+		"every":                     "incomplete schedule",
+		"every fortnight":           "can not understand fortnight",
+		"every day at":              "incomplete schedule",
+		"every day at 9":            "can not understand 9",
+		"every day at 13pm":         "can not understand 13pm",
+		"every day at 25:00":        "invalid time 25:00",
+		"every day at 9:5":          "can not understand 9:5",
+		"every day at 9am at 10am":  "can not understand at 10am",
+		"every hour at 9am":         "can not specify a time for an hourly or minutely schedule",
+		"every minute at noon":      "can not specify a time for an hourly or minutely schedule",
+		"at 9am every 5 minutes":    "can not specify a time for an hourly or minutely schedule",
+		"at 9am every day at 10am":  "can not understand 10am",
+		"every 0 days":              "can not understand 0 days",
+		"every monday and":          "incomplete schedule",
+		"every month on the 32nd":   "can not understand 32nd",
+		"every month on the last":   "incomplete schedule",
+		"every year on february 30": "can not understand 30",
+		"every year on foo 1":       "can not understand foo 1",
+		"first day of the month":    "can not understand day of the month",
+		"first monday of the week":  "can not understand week",
+		"last day in the month":     "can not understand in the month",
+		"every day tomorrow":        "can not understand tomorrow",
+		"every week on":             "incomplete schedule",
+		"every other":               "incomplete schedule",
+	}
+	for text, msg := range errs {
+		s, err := parseEN(text)
+		a.Error(err, text).Nil(s, text).
+			Equal(err.Error(), msg, text)
+	}
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package natural 将自然语言描述的时间规则转换为 [schedulers.Scheduler]
+//
+// 支持英文和中文两种语言，根据内容中是否包含汉字自动选择，具体的语法可参考 [Parse]。
+// 解析的结果最终由 [rrule] 包实现。
+//
+// [rrule]: https://pkg.go.dev/github.com/issue9/scheduled/schedulers/rrule
+package natural
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+func init() {
+	schedulers.Register("natural", Parse)
+}
+
+type clock struct{ hour, minute int }
+
+// 解析后的规则
+type schedule struct {
+	freq       rrule.Frequency
+	interval   int
+	byDay      []rrule.Weekday
+	byMonthDay []int
+	byMonth    []int
+	times      []clock // 为空表示零点
+}
+
+// 多个调度器的合集，返回其中最早的时间。
+type union []schedulers.Scheduler
+
+// Parse 解析自然语言描述的时间规则
+//
+// text 不区分大小写，loc 为时区，为 nil 时表示 [time.Local]。
+//
+// 英文的语法如下，其中 [] 表示可选，| 表示多选一：
+//
+//	[every|each] day|weekday|weekend|hour|minute [at TIMES]
+//	[every|each] monday[, wednesday and friday] [at TIMES]
+//	[every|each] week [on DAYS] [at TIMES]
+//	[every|each] month [on the 1st[ and 15th]|on the last day] [at TIMES]
+//	[every|each] year [on january 1st] [at TIMES]
+//	[every|each] N|other minutes|hours|days|weeks|months ...
+//	first|second|third|fourth|last monday of the|every month [at TIMES]
+//	last day of the|every month [at TIMES]
+//
+// TIMES 为以 and 或逗号分隔的多个时间，可以是 9am、9:30pm、17:00、noon 和 midnight。
+// 时间部分也可以放在最前面，比如 at 9am every weekday。daily 和 hourly 分别等同于 every day 和 every hour。
+//
+// 中文的语法与英文类似，比如：
+//
+//	每天早上9点
+//	每个工作日上午9点半
+//	每周一、三、五下午3点
+//	每周一到周五 9:30
+//	每月1号和15号8点
+//	每月最后一天晚上10点
+//	每月第一个周一中午
+//	每年1月1日零点
+//	每隔15分钟
+//	每2小时
+//
+// 时间可以是 9点、9点30分、9点半、9:30，可以加上凌晨、早上、上午、中午、下午和晚上等修饰，
+// 数字可以是阿拉伯数字或是中文数字。
+//
+// 在指定间隔时，以 2000-01-01 作为起始时间进行计算。
+func Parse(text string, loc *time.Location) (schedulers.Scheduler, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var s *schedule
+	var err error
+	if strings.ContainsFunc(text, func(r rune) bool { return unicode.Is(unicode.Han, r) }) {
+		s, err = parseZH(text)
+	} else {
+		s, err = parseEN(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.build(loc), nil
+}
+
+// 每小时或每分钟执行的规则不能再指定具体的时间
+func timeWithFrequency() error {
+	return localeutil.Error("can not specify a time for an hourly or minutely schedule")
+}
+
+func notUnderstood(s string) error {
+	if s = strings.TrimSpace(s); s == "" {
+		return localeutil.Error("incomplete schedule")
+	}
+	return localeutil.Error("can not understand %s", s)
+}
+
+func (s *schedule) build(loc *time.Location) schedulers.Scheduler {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, loc)
+	rule := rrule.Rule{
+		Freq:       s.freq,
+		Interval:   s.interval,
+		ByDay:      s.byDay,
+		ByMonthDay: s.byMonthDay,
+		ByMonth:    s.byMonth,
+		BySecond:   []int{0},
+		WeekStart:  time.Monday,
+	}
+
+	newSet := func(r rrule.Rule) *rrule.Set {
+		// DTSTART 仅作为计算的起点，不应该作为结果返回。
+		return &rrule.Set{DTStart: start, RRule: &r, ExDates: []time.Time{start}}
+	}
+
+	switch s.freq {
+	case rrule.Minutely:
+		return newSet(rule)
+	case rrule.Hourly:
+		rule.ByMinute = []int{0}
+		return newSet(rule)
+	}
+
+	times := s.times
+	if len(times) == 0 {
+		times = []clock{{}}
+	}
+
+	// BYHOUR 与 BYMINUTE 是笛卡尔积的关系，所以分钟数不同的时间需要拆分成多个规则。
+	minutes := make([]int, 0, len(times))
+	for _, t := range times {
+		if !slices.Contains(minutes, t.minute) {
+			minutes = append(minutes, t.minute)
+		}
+	}
+
+	sets := make(union, 0, len(minutes))
+	for _, m := range minutes {
+		r := rule
+		r.ByMinute = []int{m}
+		for _, t := range times {
+			if t.minute == m && !slices.Contains(r.ByHour, t.hour) {
+				r.ByHour = append(r.ByHour, t.hour)
+			}
+		}
+		slices.Sort(r.ByHour)
+		sets = append(sets, newSet(r))
+	}
+
+	if len(sets) == 1 {
+		return sets[0]
+	}
+	return sets
+}
+
+func (u union) Next(last time.Time) time.Time {
+	var next time.Time
+	for _, s := range u {
+		if t := s.Next(last); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (s *schedule) addTime(hour, minute int) error {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return localeutil.Error("invalid time %s", fmt.Sprintf("%02d:%02d", hour, minute))
+	}
+	s.times = append(s.times, clock{hour: hour, minute: minute})
+	return nil
+}
+
+func weekdays(days ...time.Weekday) []rrule.Weekday {
+	ret := make([]rrule.Weekday, 0, len(days))
+	for _, d := range days {
+		ret = append(ret, rrule.Weekday{Day: d})
+	}
+	return ret
+}
+
+var workdays = weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+var weekend = weekdays(time.Saturday, time.Sunday)
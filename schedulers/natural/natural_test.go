@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package natural
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var _ schedulers.Scheduler = union{}
+
+// 依次调用 Next 并与 want 比较
+func assertNext(a *assert.Assertion, s schedulers.Scheduler, last time.Time, want ...time.Time) {
+	a.TB().Helper()
+	for _, w := range want {
+		last = s.Next(last)
+		a.Equal(last, w)
+	}
+}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, loc) // 周一
+
+	s, err := Parse("every weekday at 9am", loc)
+	a.NotError(err).NotNil(s)
+	assertNext(a, s, now,
+		time.Date(2026, 10, 20, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 21, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 22, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 23, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 26, 9, 0, 0, 0, loc),
+	)
+
+	s, err = Parse("first Monday of the month at noon", loc)
+	a.NotError(err).NotNil(s)
+	assertNext(a, s, now,
+		time.Date(2026, 11, 2, 12, 0, 0, 0, loc),
+		time.Date(2026, 12, 7, 12, 0, 0, 0, loc),
+		time.Date(2027, 1, 4, 12, 0, 0, 0, loc),
+	)
+
+	s, err = Parse("每天早上9点", loc)
+	a.NotError(err).NotNil(s)
+	assertNext(a, s, now,
+		time.Date(2026, 10, 20, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 21, 9, 0, 0, 0, loc),
+	)
+
+	// 分钟数不同的多个时间
+	s, err = Parse("every day at 9am and 5:30pm", loc)
+	a.NotError(err).NotNil(s)
+	_, ok := s.(union)
+	a.True(ok)
+	assertNext(a, s, now,
+		time.Date(2026, 10, 19, 17, 30, 0, 0, loc),
+		time.Date(2026, 10, 20, 9, 0, 0, 0, loc),
+		time.Date(2026, 10, 20, 17, 30, 0, 0, loc),
+	)
+
+	s, err = Parse("每15分钟", loc)
+	a.NotError(err).NotNil(s)
+	assertNext(a, s, time.Date(2026, 10, 19, 10, 7, 3, 0, loc),
+		time.Date(2026, 10, 19, 10, 15, 0, 0, loc),
+		time.Date(2026, 10, 19, 10, 30, 0, 0, loc),
+	)
+
+	// 默认时区
+	s, err = Parse("daily", nil)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now).Location(), time.Local)
+
+	// 错误
+	s, err = Parse("every fortnight", loc)
+	a.ErrorString(err, "can not understand fortnight").Nil(s)
+	s, err = Parse("每两周半", loc)
+	a.ErrorString(err, "can not understand 半").Nil(s)
+	s, err = Parse("every", loc)
+	a.ErrorString(err, "incomplete schedule").Nil(s)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	s, err := schedulers.Parse("natural:every monday at 10:30", loc)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "natural:every monday at 10:30").
+		Equal(s.Next(time.Date(2026, 10, 19, 11, 0, 0, 0, loc)), time.Date(2026, 10, 26, 10, 30, 0, 0, loc))
+}
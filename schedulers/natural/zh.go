@@ -0,0 +1,376 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package natural
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+var zhDigits = map[rune]int{
+	'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+var zhWeekdays = map[rune]time.Weekday{
+	'一': time.Monday, '二': time.Tuesday, '三': time.Wednesday, '四': time.Thursday,
+	'五': time.Friday, '六': time.Saturday, '日': time.Sunday, '天': time.Sunday,
+	'1': time.Monday, '2': time.Tuesday, '3': time.Wednesday, '4': time.Thursday,
+	'5': time.Friday, '6': time.Saturday, '7': time.Sunday,
+}
+
+type zhParser struct {
+	text []rune
+	pos  int
+	s    *schedule
+}
+
+func parseZH(text string) (*schedule, error) {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '的' {
+			return -1
+		}
+		return r
+	}, text)
+
+	p := &zhParser{text: []rune(text), s: &schedule{interval: 1}}
+	if err := p.parseBody(); err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.text) {
+		if p.s.freq == rrule.Minutely || p.s.freq == rrule.Hourly {
+			return nil, timeWithFrequency()
+		}
+		if err := p.parseTimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.pos < len(p.text) {
+		return nil, p.fail()
+	}
+	return p.s, nil
+}
+
+func (p *zhParser) fail() error { return notUnderstood(string(p.text[min(p.pos, len(p.text)):])) }
+
+// 如果当前位置以 prefixes 中的任意一个开头，则跳过该内容并返回 true。
+func (p *zhParser) accept(prefixes ...string) bool {
+	rest := string(p.text[p.pos:])
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(rest, prefix) {
+			p.pos += len([]rune(prefix))
+			return true
+		}
+	}
+	return false
+}
+
+// 解析阿拉伯数字或是中文数字，最大支持到九十九。
+func (p *zhParser) number() (int, bool) {
+	start := p.pos
+	n := 0
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		n = n*10 + int(p.text[p.pos]-'0')
+		p.pos++
+	}
+	if p.pos > start {
+		return n, true
+	}
+
+	// 中文数字：十、十二、二十、二十三
+	tens, units, hasTen := 0, -1, false
+	for p.pos < len(p.text) {
+		r := p.text[p.pos]
+		if r == '十' && !hasTen {
+			hasTen = true
+			tens = max(units, 1)
+			units = -1
+		} else if d, found := zhDigits[r]; found && units < 0 {
+			units = d
+		} else {
+			break
+		}
+		p.pos++
+	}
+
+	switch {
+	case p.pos == start:
+		return 0, false
+	case hasTen:
+		return tens*10 + max(units, 0), true
+	default:
+		return units, true
+	}
+}
+
+func (p *zhParser) separator() bool { return p.accept("、", "，", ",", "和", "及", "与") }
+
+func (p *zhParser) parseBody() error {
+	every := p.accept("每隔", "每个", "每")
+
+	start := p.pos
+	if n, ok := p.number(); ok {
+		if !every || n <= 0 {
+			p.pos = start
+			return p.fail()
+		}
+		p.s.interval = n
+		return p.parseUnit(true)
+	}
+
+	switch {
+	case p.accept("工作日"):
+		p.s.freq = rrule.Weekly
+		p.s.byDay = workdays
+		return nil
+	case p.accept("周末"):
+		p.s.freq = rrule.Weekly
+		p.s.byDay = weekend
+		return nil
+	case !every: // 周一 等省略了“每”的写法
+		if p.accept("周", "星期", "礼拜") {
+			p.s.freq = rrule.Weekly
+			return p.parseWeekdays()
+		}
+		return p.fail()
+	}
+	return p.parseUnit(false)
+}
+
+// 时间单位以及之后的日期
+//
+// interval 表示是否指定了间隔，指定间隔时，单位之后不能再跟具体的日期。
+func (p *zhParser) parseUnit(interval bool) error {
+	switch {
+	case p.accept("分钟"):
+		p.s.freq = rrule.Minutely
+	case p.accept("小时", "钟头", "个小时", "个钟头"):
+		p.s.freq = rrule.Hourly
+	case p.accept("天", "日"):
+		p.s.freq = rrule.Daily
+	case p.accept("周", "星期", "礼拜", "个星期", "个礼拜"):
+		p.s.freq = rrule.Weekly
+		if interval || !p.isWeekday() {
+			p.s.byDay = weekdays(time.Monday)
+			return nil
+		}
+		return p.parseWeekdays()
+	case p.accept("个月", "月"):
+		p.s.freq = rrule.Monthly
+		if interval {
+			p.s.byMonthDay = []int{1}
+			return nil
+		}
+		return p.parseMonthDays()
+	case p.accept("年"):
+		p.s.freq = rrule.Yearly
+		if interval {
+			p.s.byMonth, p.s.byMonthDay = []int{1}, []int{1}
+			return nil
+		}
+		return p.parseYearDay()
+	default:
+		return p.fail()
+	}
+	return nil
+}
+
+func (p *zhParser) isWeekday() bool {
+	if p.pos >= len(p.text) {
+		return false
+	}
+	_, found := zhWeekdays[p.text[p.pos]]
+	return found
+}
+
+// 一、三、五，一到五，一至周五 等
+func (p *zhParser) parseWeekdays() error {
+	for {
+		p.accept("周", "星期", "礼拜")
+		if !p.isWeekday() {
+			return p.fail()
+		}
+		start := p.pos
+		from := zhWeekdays[p.text[p.pos]]
+		p.pos++
+
+		if p.accept("到", "至", "-", "~") {
+			p.accept("周", "星期", "礼拜")
+			if !p.isWeekday() {
+				return p.fail()
+			}
+			to := zhWeekdays[p.text[p.pos]]
+			p.pos++
+
+			// 周日为一周的最后一天，所以先转换为周一为 1，周日为 7 的形式。
+			first, last := (int(from)+6)%7+1, (int(to)+6)%7+1
+			if first > last {
+				p.pos = start
+				return p.fail()
+			}
+			for d := first; d <= last; d++ {
+				p.s.byDay = append(p.s.byDay, rrule.Weekday{Day: time.Weekday(d % 7)})
+			}
+		} else {
+			p.s.byDay = append(p.s.byDay, rrule.Weekday{Day: from})
+		}
+
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+// 1号、1日和15日、最后一天、第一个周一、最后一个周五
+func (p *zhParser) parseMonthDays() error {
+	switch {
+	case p.accept("最后一天", "最后1天"):
+		p.s.byMonthDay = []int{-1}
+		return nil
+	case p.accept("最后一个", "最后1个"):
+		return p.parseNthWeekday(-1)
+	case p.accept("第"):
+		start := p.pos - 1
+		n, ok := p.number()
+		if !ok || n < 1 || n > 5 || !p.accept("个") {
+			p.pos = start
+			return p.fail()
+		}
+		return p.parseNthWeekday(n)
+	}
+
+	for {
+		day, ok := p.number()
+		if !ok {
+			if len(p.s.byMonthDay) == 0 { // 每月 之后没有日期，默认为 1 号。
+				p.s.byMonthDay = []int{1}
+				return nil
+			}
+			return p.fail()
+		}
+		if day < 1 || day > 31 || !p.accept("号", "日") {
+			return p.fail()
+		}
+		p.s.byMonthDay = append(p.s.byMonthDay, day)
+
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+func (p *zhParser) parseNthWeekday(n int) error {
+	if !p.accept("周", "星期", "礼拜") || !p.isWeekday() {
+		return p.fail()
+	}
+	p.s.byDay = []rrule.Weekday{{N: n, Day: zhWeekdays[p.text[p.pos]]}}
+	p.pos++
+	return nil
+}
+
+// 1月1日
+func (p *zhParser) parseYearDay() error {
+	month, ok := p.number()
+	if !ok {
+		p.s.byMonth, p.s.byMonthDay = []int{1}, []int{1}
+		return nil
+	}
+	if month < 1 || month > 12 || !p.accept("月") {
+		return p.fail()
+	}
+
+	day, ok := p.number()
+	if !ok || day < 1 || day > time.Date(2000, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day() || !p.accept("日", "号") {
+		return p.fail()
+	}
+
+	p.s.byMonth = []int{month}
+	p.s.byMonthDay = []int{day}
+	return nil
+}
+
+func (p *zhParser) parseTimes() error {
+	for {
+		if err := p.parseTime(); err != nil {
+			return err
+		}
+		if !p.separator() {
+			return nil
+		}
+	}
+}
+
+// [凌晨|早上|上午|中午|下午|晚上|午夜] 9点[30分|半|整]、9:30、中午、午夜、零点
+func (p *zhParser) parseTime() error {
+	pm := false
+	noon := false
+	night := false // 12 点表示 0 点
+	midnight := false
+	switch {
+	case p.accept("午夜"):
+		midnight, night = true, true
+	case p.accept("凌晨"):
+		night = true
+	case p.accept("早上", "早晨", "清晨", "上午"):
+	case p.accept("中午"):
+		noon = true
+	case p.accept("下午", "傍晚"):
+		pm = true
+	case p.accept("晚上"):
+		pm, night = true, true
+	}
+
+	start := p.pos
+	hour, ok := p.number()
+	if !ok {
+		switch {
+		case noon:
+			return p.s.addTime(12, 0)
+		case midnight:
+			return p.s.addTime(0, 0)
+		}
+		return p.fail()
+	}
+
+	minute := 0
+	switch {
+	case p.accept(":", "："):
+		m := p.pos
+		if minute, ok = p.number(); !ok || p.pos-m != 2 {
+			p.pos = start
+			return p.fail()
+		}
+	case p.accept("点", "时"):
+		switch {
+		case p.accept("半"):
+			minute = 30
+		case p.accept("整"):
+		default:
+			if m, ok := p.number(); ok {
+				minute = m
+				p.accept("分")
+			}
+		}
+	default:
+		p.pos = start
+		return p.fail()
+	}
+
+	switch {
+	case night && hour == 12: // 晚上12点、凌晨12点
+		hour = 0
+	case pm && hour < 12:
+		hour += 12
+	case noon && hour < 6: // 中午1点
+		hour += 12
+	}
+
+	return p.s.addTime(hour, minute)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package natural
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers/rrule"
+)
+
+func TestZHParser_number(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]int{
+		"0":    0,
+		"15":   15,
+		"零":    0,
+		"一":    1,
+		"两":    2,
+		"十":    10,
+		"十二":   12,
+		"二十":   20,
+		"二十三":  23,
+		"九十九":  99,
+		"三十一号": 31,
+	}
+	for text, want := range data {
+		p := &zhParser{text: []rune(text)}
+		n, ok := p.number()
+		a.True(ok, text).Equal(n, want, text)
+	}
+
+	p := &zhParser{text: []rune("点")}
+	_, ok := p.number()
+	a.False(ok).Equal(p.pos, 0)
+}
+
+func TestParseZH(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]*schedule{
+		"每天":           {freq: rrule.Daily, interval: 1},
+		"每日早上9点":       {freq: rrule.Daily, interval: 1, times: []clock{{9, 0}}},
+		"每天 下午3点半":     {freq: rrule.Daily, interval: 1, times: []clock{{15, 30}}},
+		"每天晚上十点二十分":    {freq: rrule.Daily, interval: 1, times: []clock{{22, 20}}},
+		"每天中午":         {freq: rrule.Daily, interval: 1, times: []clock{{12, 0}}},
+		"每天中午1点":       {freq: rrule.Daily, interval: 1, times: []clock{{13, 0}}},
+		"每天午夜":         {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}}},
+		"每天午夜12点":      {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}}},
+		"每天晚上12点":      {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}}},
+		"每天凌晨12点":      {freq: rrule.Daily, interval: 1, times: []clock{{0, 0}}},
+		"每天凌晨1点":       {freq: rrule.Daily, interval: 1, times: []clock{{1, 0}}},
+		"每天8:00和17:30": {freq: rrule.Daily, interval: 1, times: []clock{{8, 0}, {17, 30}}},
+		"每天9点整":        {freq: rrule.Daily, interval: 1, times: []clock{{9, 0}}},
+		"每小时":          {freq: rrule.Hourly, interval: 1},
+		"每2小时":         {freq: rrule.Hourly, interval: 2},
+		"每隔两个钟头":       {freq: rrule.Hourly, interval: 2},
+		"每15分钟":        {freq: rrule.Minutely, interval: 15},
+		"每隔三天早上8点":     {freq: rrule.Daily, interval: 3, times: []clock{{8, 0}}},
+		"工作日9:30":      {freq: rrule.Weekly, interval: 1, byDay: workdays, times: []clock{{9, 30}}},
+		"每个工作日":        {freq: rrule.Weekly, interval: 1, byDay: workdays},
+		"每周末上午10点":     {freq: rrule.Weekly, interval: 1, byDay: weekend, times: []clock{{10, 0}}},
+		"每周":           {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday)},
+		"每两周":          {freq: rrule.Weekly, interval: 2, byDay: weekdays(time.Monday)},
+		"每周一":          {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday)},
+		"每周日":          {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Sunday)},
+		"每星期天":         {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Sunday)},
+		"每周一、三、五下午3点": {
+			freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Monday, time.Wednesday, time.Friday), times: []clock{{15, 0}},
+		},
+		"周一到周五9:30": {freq: rrule.Weekly, interval: 1, byDay: workdays, times: []clock{{9, 30}}},
+		"每周五至日":     {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Friday, time.Saturday, time.Sunday)},
+		"礼拜二和礼拜四":   {freq: rrule.Weekly, interval: 1, byDay: weekdays(time.Tuesday, time.Thursday)},
+		"每月":        {freq: rrule.Monthly, interval: 1, byMonthDay: []int{1}},
+		"每3个月":      {freq: rrule.Monthly, interval: 3, byMonthDay: []int{1}},
+		"每月1号和15号8点": {
+			freq: rrule.Monthly, interval: 1, byMonthDay: []int{1, 15}, times: []clock{{8, 0}},
+		},
+		"每月最后一天晚上10点": {freq: rrule.Monthly, interval: 1, byMonthDay: []int{-1}, times: []clock{{22, 0}}},
+		"每月第一个周一中午":   {freq: rrule.Monthly, interval: 1, byDay: []rrule.Weekday{{N: 1, Day: time.Monday}}, times: []clock{{12, 0}}},
+		"每个月的最后一个星期五": {freq: rrule.Monthly, interval: 1, byDay: []rrule.Weekday{{N: -1, Day: time.Friday}}},
+		"每年":          {freq: rrule.Yearly, interval: 1, byMonth: []int{1}, byMonthDay: []int{1}},
+		"每年1月1日零点":    {freq: rrule.Yearly, interval: 1, byMonth: []int{1}, byMonthDay: []int{1}, times: []clock{{0, 0}}},
+		"每年十二月二十五号":   {freq: rrule.Yearly, interval: 1, byMonth: []int{12}, byMonthDay: []int{25}},
+	}
+	for text, want := range data {
+		s, err := parseZH(text)
+		a.NotError(err, text).Equal(s, want, text)
+	}
+
+	errs := map[string]string{
+		"":         "incomplete schedule",
+		"每":        "incomplete schedule",
+		"明天":       "can not understand 明天",
+		"3天":       "can not understand 3天",
+		"每0天":      "can not understand 0天",
+		"每周五到一":    "can not understand 五到一",
+		"每周八":      "can not understand 八",
+		"每月32号":    "can not understand 号",
+		"每月第六个周一":  "can not understand 第六个周一",
+		"每月第一个":    "incomplete schedule",
+		"每年2月30日":  "can not understand 日",
+		"每年13月1日":  "can not understand 月1日",
+		"每天25点":    "invalid time 25:00",
+		"每天9":      "can not understand 9",
+		"每天9:5":    "can not understand 9:5",
+		"每天9点和":    "incomplete schedule",
+		"每小时9点":    "can not specify a time for an hourly or minutely schedule",
+		"每天早上9点明天": "can not understand 明天",
+	}
+	for text, msg := range errs {
+		s, err := parseZH(text)
+		a.Error(err, text).Nil(s, text)
+		if msg != "" {
+			a.Equal(err.Error(), msg, text)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import "time"
+
+// Offset 将 s 生成的所有时间点偏移 d
+//
+// d 为负数表示提前，为正数表示延后。
+// 偏移之后的时间点依然满足大于 last 的要求：
+// 以 last-d 作为参数向 s 获取时间点，即在 s 中向前或是向后查找相应的时间。
+// s 返回零值时，同样返回零值。
+func Offset(s Scheduler, d time.Duration) Scheduler {
+	return SchedulerFunc(func(last time.Time) time.Time {
+		next := s.Next(last.Add(-d))
+		if next.IsZero() {
+			return next
+		}
+		return next.Add(d)
+	})
+}
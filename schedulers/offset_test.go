@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestOffset(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 每天 2 点
+	base := SchedulerFunc(func(last time.Time) time.Time {
+		next := time.Date(last.Year(), last.Month(), last.Day(), 2, 0, 0, 0, last.Location())
+		if !next.After(last) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	})
+
+	// 提前 30 分钟
+	s := Offset(base, -30*time.Minute)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 1, 1, 30, 0, 0, time.UTC)).
+		Equal(s.Next(time.Date(2026, 1, 1, 1, 30, 0, 0, time.UTC)), time.Date(2026, 1, 2, 1, 30, 0, 0, time.UTC)).
+		Equal(s.Next(time.Date(2026, 1, 1, 1, 45, 0, 0, time.UTC)), time.Date(2026, 1, 2, 1, 30, 0, 0, time.UTC))
+
+	// 提前 3 小时，需要跨越日期
+	s = Offset(base, -3*time.Hour)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)).
+		Equal(s.Next(time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)), time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC))
+
+	// 延后 10 分钟
+	s = Offset(base, 10*time.Minute)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 2, 5, 0, 0, time.UTC)), time.Date(2026, 1, 1, 2, 10, 0, 0, time.UTC)).
+		Equal(s.Next(time.Date(2026, 1, 1, 2, 10, 0, 0, time.UTC)), time.Date(2026, 1, 2, 2, 10, 0, 0, time.UTC))
+
+	// 零值
+	s = Offset(SchedulerFunc(func(time.Time) time.Time { return time.Time{} }), -time.Hour)
+	a.True(s.Next(time.Now()).IsZero())
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+// RandSource 根据 last 生成随机数源
+//
+// 为了满足 [Scheduler] 相同的 last 返回相同值的要求，
+// 相同的 last 必须返回相同序列的随机数源。
+type RandSource func(last time.Time) rand.Source
+
+// SeedSource 以 seed 和 last 作为种子生成 [rand.PCG] 随机数源
+func SeedSource(seed uint64) RandSource {
+	return func(last time.Time) rand.Source {
+		return rand.NewPCG(seed, uint64(last.UnixNano()))
+	}
+}
+
+// Poisson 以泊松过程执行的调度器
+//
+// 相邻两次执行的间隔服从指数分布，mean 为其平均值，即 1/λ，必须大于 0；
+// src 为随机数源，为空表示 SeedSource(0)。
+func Poisson(mean time.Duration, src RandSource) (Scheduler, error) {
+	if mean <= 0 {
+		return nil, &InvalidError{Spec: mean.String(), Err: localeutil.Error("invalid duration %s", mean.String())}
+	}
+
+	if src == nil {
+		src = SeedSource(0)
+	}
+
+	return SchedulerFunc(func(last time.Time) time.Time {
+		d := time.Duration(rand.New(src(last)).ExpFloat64() * float64(mean))
+		return last.Add(max(d, 1))
+	}), nil
+}
+
+// Uniform 以随机间隔执行的调度器
+//
+// 相邻两次执行的间隔均匀分布在 [min, max] 之间，且 0 < min <= max；
+// src 为随机数源，为空表示 SeedSource(0)。
+func Uniform(min, max time.Duration, src RandSource) (Scheduler, error) {
+	if min <= 0 || min > max {
+		spec := "[" + min.String() + "," + max.String() + "]"
+		return nil, &InvalidError{Spec: spec, Err: localeutil.Error("invalid duration %s", spec)}
+	}
+
+	if src == nil {
+		src = SeedSource(0)
+	}
+
+	return SchedulerFunc(func(last time.Time) time.Time {
+		return last.Add(min + time.Duration(rand.New(src(last)).Int64N(int64(max-min)+1)))
+	}), nil
+}
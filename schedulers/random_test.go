@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestPoisson(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := Poisson(0, nil)
+	a.Error(err).Nil(s)
+	var target *InvalidError
+	a.True(errors.As(err, &target))
+
+	s, err = Poisson(time.Second, SeedSource(1))
+	a.NotError(err).NotNil(s)
+
+	last := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.Equal(s.Next(last), s.Next(last)) // 相同的 last 返回相同的值
+
+	// 不同的种子返回不同的值
+	s2, err := Poisson(time.Second, SeedSource(2))
+	a.NotError(err).NotNil(s2)
+	a.NotEqual(s.Next(last), s2.Next(last))
+
+	// 平均值
+	const count = 20000
+	start := last
+	for range count {
+		next := s.Next(last)
+		a.True(next.After(last))
+		last = next
+	}
+	mean := last.Sub(start).Seconds() / count
+	a.True(math.Abs(mean-1) < 0.05, mean)
+
+	// 默认的随机数源
+	s, err = Poisson(time.Minute, nil)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(start), s.Next(start))
+}
+
+func TestUniform(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := Uniform(0, time.Second, nil)
+	a.Error(err).Nil(s)
+	s, err = Uniform(2*time.Second, time.Second, nil)
+	a.Error(err).Nil(s)
+	var target *InvalidError
+	a.True(errors.As(err, &target)).Equal(target.Spec, "[2s,1s]")
+
+	s, err = Uniform(time.Second, 3*time.Second, SeedSource(1))
+	a.NotError(err).NotNil(s)
+
+	last := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.Equal(s.Next(last), s.Next(last))
+
+	var lo, hi time.Duration = time.Hour, 0
+	for range 10000 {
+		next := s.Next(last)
+		d := next.Sub(last)
+		a.True(d >= time.Second && d <= 3*time.Second, d)
+		lo, hi = min(lo, d), max(hi, d)
+		last = next
+	}
+	a.True(lo < 1100*time.Millisecond, lo).True(hi > 2900*time.Millisecond, hi)
+
+	// min == max
+	s, err = Uniform(time.Second, time.Second, nil)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(last), last.Add(time.Second))
+}
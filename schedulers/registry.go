@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+// Factory 根据文本生成 [Scheduler] 的函数
+//
+// spec 为去掉了前缀之后的内容；loc 为时区，不会为 nil。
+type Factory func(spec string, loc *time.Location) (Scheduler, error)
+
+// Spec 由 [Parse] 返回的调度器
+//
+// 除了实现 [Scheduler] 之外，还可以通过 [Spec.String] 还原为 [Parse] 可用的文本。
+// 同时也实现了 [Notifier]，会转发给 [Factory] 生成的调度器。
+type Spec struct {
+	Scheduler
+	kind, spec string
+}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register 注册生成 [Scheduler] 的函数
+//
+// kind 为类型名称，在 [Parse] 中以 kind: 作为前缀进行区分，
+// 比如 cron 包以 cron 为名称注册，那么 cron:0 * * * * * 将由 cron 包进行解析。
+// 内置的调度器在各自的包中注册，需要引用相应的包才能使用。
+//
+// kind 不能为空或是包含冒号和空白字符，且不能重复注册，否则 panic。
+func Register(kind string, f Factory) {
+	if kind == "" || strings.ContainsFunc(kind, func(r rune) bool { return r == ':' || r == ' ' || r == '\t' }) {
+		panic("参数 kind 格式不正确")
+	}
+	if f == nil {
+		panic("参数 f 不能为空")
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if _, found := factories[kind]; found {
+		panic("已经存在同名的 " + kind)
+	}
+	factories[kind] = f
+}
+
+// Kinds 返回所有已经注册的类型名称
+func Kinds() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	kinds := make([]string, 0, len(factories))
+	for k := range factories {
+		kinds = append(kinds, k)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// Parse 将 kind:spec 格式的文本转换为 [Scheduler]
+//
+// kind 为 [Register] 注册的类型名称，spec 交由相应的 [Factory] 进行解析；
+// loc 为时区，为 nil 表示 [time.Local]。
+// 出错时返回 [InvalidError]。
+func Parse(text string, loc *time.Location) (*Spec, error) {
+	kind, spec, found := strings.Cut(strings.TrimSpace(text), ":")
+	if !found {
+		return nil, &InvalidError{Spec: text, Err: localeutil.Error("missing %s", "kind:")}
+	}
+	spec = strings.TrimSpace(spec)
+
+	factoriesMu.RLock()
+	f, found := factories[kind]
+	factoriesMu.RUnlock()
+	if !found {
+		return nil, &InvalidError{Spec: text, Err: localeutil.Error("invalid value %s", kind)}
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	s, err := f(spec, loc)
+	if err != nil {
+		if ie, ok := err.(*InvalidError); ok {
+			err = ie.Err
+		}
+		return nil, &InvalidError{Spec: text, Err: err}
+	}
+	return &Spec{Scheduler: s, kind: kind, spec: spec}, nil
+}
+
+// Kind 类型名称
+func (s *Spec) Kind() string { return s.kind }
+
+// String 返回可以被 [Parse] 解析的文本
+func (s *Spec) String() string { return s.kind + ":" + s.spec }
+
+func (s *Spec) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// Notify 实现 [Notifier] 接口
+//
+// 如果 [Factory] 生成的调度器未实现 [Notifier]，那么 f 永远不会被调用。
+func (s *Spec) Notify(f func()) (cancel func()) {
+	if n, ok := s.Scheduler.(Notifier); ok {
+		return n.Notify(f)
+	}
+	return func() {}
+}
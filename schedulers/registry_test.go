@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+	"github.com/issue9/localeutil"
+)
+
+var _ Notifier = &Spec{}
+
+type notifier struct {
+	SchedulerFunc
+	hooks []func()
+}
+
+func (n *notifier) Notify(f func()) func() {
+	n.hooks = append(n.hooks, f)
+	return func() { n.hooks = nil }
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 每隔 n 小时
+	Register("test-hours", func(spec string, loc *time.Location) (Scheduler, error) {
+		d, err := time.ParseDuration(spec + "h")
+		if err != nil {
+			return nil, localeutil.Error("invalid duration %s", spec)
+		}
+		return SchedulerFunc(func(last time.Time) time.Time { return last.In(loc).Add(d) }), nil
+	})
+	Register("test-invalid", func(spec string, _ *time.Location) (Scheduler, error) {
+		return nil, &InvalidError{Spec: spec, Err: localeutil.Error("invalid value %s", spec)}
+	})
+
+	a.True(slices.Contains(Kinds(), "test-hours")).
+		True(slices.IsSorted(Kinds()))
+
+	a.PanicString(func() {
+		Register("test-hours", func(string, *time.Location) (Scheduler, error) { return nil, nil })
+	}, "已经存在同名的 test-hours")
+	a.PanicString(func() {
+		Register("a:b", func(string, *time.Location) (Scheduler, error) { return nil, nil })
+	}, "参数 kind 格式不正确")
+	a.PanicString(func() {
+		Register("", func(string, *time.Location) (Scheduler, error) { return nil, nil })
+	}, "参数 kind 格式不正确")
+	a.PanicString(func() {
+		Register("test-nil", nil)
+	}, "参数 f 不能为空")
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	s, err := Parse(" test-hours: 2 ", loc)
+	a.NotError(err).NotNil(s).
+		Equal(s.Kind(), "test-hours").
+		Equal(s.String(), "test-hours:2").
+		Equal(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 1, 10, 0, 0, 0, loc))
+	text, err := s.MarshalText()
+	a.NotError(err).Equal(string(text), "test-hours:2")
+
+	// round-trip
+	s2, err := Parse(s.String(), loc)
+	a.NotError(err).Equal(s2.String(), s.String())
+
+	s, err = Parse("test-hours:2", nil)
+	a.NotError(err).NotNil(s).
+		Equal(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Location(), time.Local)
+
+	var target *InvalidError
+	for _, text := range []string{"", "test-hours", "not-exists:1", "test-hours:x", "test-invalid:x"} {
+		s, err := Parse(text, loc)
+		a.Error(err, text).Nil(s).
+			True(errors.As(err, &target)).
+			Equal(target.Spec, text)
+	}
+
+	// 不会重复包装 InvalidError
+	_, err = Parse("test-invalid:x", loc)
+	a.True(errors.As(err, &target)).
+		False(errors.As(target.Err, new(*InvalidError)))
+
+	// 转发 Notifier
+	n := &notifier{SchedulerFunc: func(last time.Time) time.Time { return last }}
+	Register("test-notify", func(string, *time.Location) (Scheduler, error) { return n, nil })
+	s, err = Parse("test-notify:x", loc)
+	a.NotError(err).NotNil(s)
+	var called bool
+	cancel := s.Notify(func() { called = true })
+	a.Length(n.hooks, 1)
+	n.hooks[0]()
+	a.True(called)
+	cancel()
+	a.Empty(n.hooks)
+
+	// 未实现 Notifier
+	s, err = Parse("test-hours:2", loc)
+	a.NotError(err).NotNil(s)
+	s.Notify(func() { called = false })()
+	a.True(called)
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package repeat
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+// Duration ISO 8601 中的时间段
+//
+// 年、月、周和日以日历的方式计算，即采用 [time.Time.AddDate]；
+// 时、分和秒则是固定的时长。
+type Duration struct {
+	Years, Months, Weeks, Days int
+	Hours, Minutes             int
+	Seconds                    float64
+}
+
+// ParseDuration 解析 ISO 8601 格式的时间段
+//
+// 格式为 PnYnMnWnDTnHnMnS，比如 P1M、P1DT12H、PT0.5S，仅秒允许小数。
+func ParseDuration(s string) (Duration, error) {
+	var d Duration
+
+	v, found := strings.CutPrefix(s, "P")
+	if !found || v == "" || strings.HasSuffix(v, "T") {
+		return d, localeutil.Error("invalid duration %s", s)
+	}
+
+	date, clock, _ := strings.Cut(v, "T")
+	if err := parseUnits(date, "YMWD", func(u byte, n string) error {
+		i, err := strconv.Atoi(n)
+		switch u {
+		case 'Y':
+			d.Years = i
+		case 'M':
+			d.Months = i
+		case 'W':
+			d.Weeks = i
+		case 'D':
+			d.Days = i
+		}
+		return err
+	}); err != nil {
+		return Duration{}, localeutil.Error("invalid duration %s", s)
+	}
+
+	if err := parseUnits(clock, "HMS", func(u byte, n string) error {
+		if u == 'S' {
+			f, err := strconv.ParseFloat(n, 64)
+			if err == nil && (f < 0 || math.IsInf(f, 0) || math.IsNaN(f)) {
+				return strconv.ErrSyntax
+			}
+			d.Seconds = f
+			return err
+		}
+
+		i, err := strconv.Atoi(n)
+		if u == 'H' {
+			d.Hours = i
+		} else {
+			d.Minutes = i
+		}
+		return err
+	}); err != nil {
+		return Duration{}, localeutil.Error("invalid duration %s", s)
+	}
+
+	return d, nil
+}
+
+// 依次解析 s 中以 units 为单位的各个值
+//
+// units 中的单位必须按顺序出现，且每个最多出现一次。
+func parseUnits(s, units string, f func(byte, string) error) error {
+	for s != "" {
+		index := strings.IndexAny(s, units)
+		if index <= 0 {
+			return strconv.ErrSyntax
+		}
+
+		u := s[index]
+		n := s[:index]
+		if strings.HasPrefix(n, "-") || strings.HasPrefix(n, "+") {
+			return strconv.ErrSyntax
+		}
+		if err := f(u, n); err != nil {
+			return err
+		}
+
+		units = units[strings.IndexByte(units, u)+1:]
+		s = s[index+1:]
+	}
+	return nil
+}
+
+// IsZero 是否为零值
+func (d Duration) IsZero() bool { return d == Duration{} }
+
+// Add 返回 t 加上 n 倍的 d 之后的时间
+//
+// 年、月、周和日以 t 所在时区的日历计算，之后再加上时、分、秒的时长。
+func (d Duration) Add(t time.Time, n int) time.Time {
+	t = t.AddDate(n*d.Years, n*d.Months, n*(d.Weeks*7+d.Days))
+	clock := time.Duration(d.Hours)*time.Hour + time.Duration(d.Minutes)*time.Minute +
+		time.Duration(d.Seconds*float64(time.Second))
+	return t.Add(time.Duration(n) * clock)
+}
+
+func (d Duration) String() string {
+	b := &strings.Builder{}
+	b.WriteByte('P')
+
+	write := func(n int, u byte) {
+		if n != 0 {
+			b.WriteString(strconv.Itoa(n))
+			b.WriteByte(u)
+		}
+	}
+	write(d.Years, 'Y')
+	write(d.Months, 'M')
+	write(d.Weeks, 'W')
+	write(d.Days, 'D')
+
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 {
+		b.WriteByte('T')
+		write(d.Hours, 'H')
+		write(d.Minutes, 'M')
+		if d.Seconds != 0 {
+			b.WriteString(strconv.FormatFloat(d.Seconds, 'f', -1, 64))
+			b.WriteByte('S')
+		}
+	}
+
+	if b.Len() == 1 {
+		b.WriteString("T0S")
+	}
+	return b.String()
+}
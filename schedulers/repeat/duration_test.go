@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestParseDuration(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]Duration{
+		"P1D":              {Days: 1},
+		"P1M":              {Months: 1},
+		"PT1M":             {Minutes: 1},
+		"P2W":              {Weeks: 2},
+		"P1Y2M3W4DT5H6M7S": {Years: 1, Months: 2, Weeks: 3, Days: 4, Hours: 5, Minutes: 6, Seconds: 7},
+		"PT0.5S":           {Seconds: 0.5},
+		"PT36H":            {Hours: 36},
+	}
+	for s, want := range data {
+		d, err := ParseDuration(s)
+		a.NotError(err, s).Equal(d, want, s).Equal(d.String(), s)
+	}
+
+	for _, s := range []string{"", "P", "1D", "PT", "P1DT", "PD", "P1H", "PT1D", "P1M1Y", "P1D1D", "P-1D", "P1.5D", "PT1.5H", "PT-1S", "PTNaNS"} {
+		d, err := ParseDuration(s)
+		a.Error(err, s).True(d.IsZero())
+	}
+
+	a.Equal(Duration{}.String(), "PT0S")
+}
+
+func TestDuration_Add(t *testing.T) {
+	a := assert.New(t, false)
+
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	a.Equal(Duration{Months: 1}.Add(start, 1), time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)).
+		Equal(Duration{Months: 1}.Add(start, 2), time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)).
+		Equal(Duration{Weeks: 1, Days: 1, Hours: 1}.Add(start, 2), time.Date(2026, 2, 16, 11, 0, 0, 0, time.UTC)).
+		Equal(Duration{Seconds: 0.5}.Add(start, 3), start.Add(1500*time.Millisecond))
+
+	// 跨越夏令时，P1D 保持本地时间不变
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+	start = time.Date(2026, 3, 7, 9, 0, 0, 0, ny)
+	a.Equal(Duration{Days: 1}.Add(start, 1), time.Date(2026, 3, 8, 9, 0, 0, 0, ny)).
+		Equal(Duration{Hours: 24}.Add(start, 1), time.Date(2026, 3, 8, 10, 0, 0, 0, ny))
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package repeat 实现了 ISO 8601 中重复时间间隔的 [schedulers.Scheduler] 接口
+//
+// 支持以下两种格式：
+//
+//	Rn/start/duration，比如 R5/2026-01-01T00:00:00Z/P1D；
+//	Rn/start/end，比如 R/2026-01-01T00:00:00Z/2026-01-01T08:00:00Z。
+//
+// 其中 n 表示执行的次数，省略或是为 -1 表示不限次数。
+package repeat
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func init() {
+	schedulers.Register("repeat", func(spec string, loc *time.Location) (schedulers.Scheduler, error) {
+		s, err := Parse(spec, loc)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+}
+
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"20060102T150405Z07:00",
+	"20060102T150405",
+	time.DateOnly,
+}
+
+// Repeat 重复的时间间隔
+type Repeat struct {
+	count int // 小于 0 表示不限次数
+	start time.Time
+
+	d   Duration
+	end time.Time // 不为零值表示采用 start/end 的格式
+}
+
+// New 声明 [Repeat]
+//
+// count 表示执行的次数，小于 0 表示不限次数；
+// start 表示第一次执行的时间，日历的计算以 start 的时区为准；
+// d 表示时间间隔，必须大于 0。
+func New(count int, start time.Time, d Duration) (*Repeat, error) {
+	if !d.Add(start, 1).After(start) {
+		return nil, localeutil.Error("invalid duration %s", d.String())
+	}
+	return &Repeat{count: count, start: start, d: d}, nil
+}
+
+// Parse 解析 ISO 8601 格式的重复时间间隔
+//
+// 未指定时区的时间采用 loc，同时时间间隔中的日历计算也基于 loc。
+// loc 为 nil 时，表示采用 [time.Local]。
+func Parse(spec string, loc *time.Location) (*Repeat, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	fs := strings.Split(spec, "/")
+	if len(fs) != 3 || !strings.HasPrefix(fs[0], "R") {
+		return nil, localeutil.Error("invalid repeating interval %s", spec)
+	}
+
+	count := -1
+	if n := fs[0][1:]; n != "" {
+		var err error
+		if count, err = strconv.Atoi(n); err != nil || count < -1 {
+			return nil, localeutil.Error("invalid repeating interval %s", spec)
+		}
+	}
+
+	start, err := parseTime(fs[1], loc)
+	if err != nil {
+		return nil, err
+	}
+	start = start.In(loc)
+
+	if strings.HasPrefix(fs[2], "P") {
+		d, err := ParseDuration(fs[2])
+		if err != nil {
+			return nil, err
+		}
+		return New(count, start, d)
+	}
+
+	end, err := parseTime(fs[2], loc)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(start) {
+		return nil, localeutil.Error("invalid repeating interval %s", spec)
+	}
+	return &Repeat{count: count, start: start, end: end.In(loc)}, nil
+}
+
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, localeutil.Error("invalid date %s", s)
+}
+
+// 第 n 次执行的时间，从 0 开始。
+func (r *Repeat) at(n int) time.Time {
+	if !r.end.IsZero() {
+		return r.start.Add(time.Duration(n) * r.end.Sub(r.start))
+	}
+	return r.d.Add(r.start, n)
+}
+
+func (r *Repeat) Next(last time.Time) time.Time {
+	var n int
+	if !last.Before(r.start) {
+		// 以第一个时间间隔估算 n，再前后调整。
+		n = int(last.Sub(r.start) / r.at(1).Sub(r.start))
+		for n > 0 && r.at(n-1).After(last) {
+			n--
+		}
+		for !r.at(n).After(last) {
+			n++
+		}
+	}
+
+	if r.count >= 0 && n >= r.count {
+		return time.Time{}
+	}
+	return r.at(n)
+}
+
+// String 返回 Rn/start/duration 或是 Rn/start/end 格式的内容
+func (r *Repeat) String() string {
+	b := &strings.Builder{}
+	b.WriteByte('R')
+	if r.count >= 0 {
+		b.WriteString(strconv.Itoa(r.count))
+	}
+	b.WriteByte('/')
+	b.WriteString(r.start.Format(time.RFC3339Nano))
+	b.WriteByte('/')
+	if r.end.IsZero() {
+		b.WriteString(r.d.String())
+	} else {
+		b.WriteString(r.end.Format(time.RFC3339Nano))
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package repeat
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var _ schedulers.Scheduler = &Repeat{}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	r, err := Parse("R5/2026-01-01T00:00:00Z/P1D", loc)
+	a.NotError(err).NotNil(r).
+		Equal(r.count, 5).
+		Equal(r.start, time.Date(2026, 1, 1, 8, 0, 0, 0, loc)).
+		Equal(r.String(), "R5/2026-01-01T08:00:00+08:00/P1D")
+
+	r, err = Parse("R/2026-01-01T09:00:00/2026-01-01T17:00:00", loc)
+	a.NotError(err).NotNil(r).
+		Equal(r.count, -1).
+		Equal(r.start, time.Date(2026, 1, 1, 9, 0, 0, 0, loc)).
+		Equal(r.String(), "R/2026-01-01T09:00:00+08:00/2026-01-01T17:00:00+08:00")
+
+	r, err = Parse("R-1/20260101T090000Z/P1M", loc)
+	a.NotError(err).NotNil(r).Equal(r.count, -1)
+
+	r, err = Parse("R3/2026-01-01/PT1H", nil)
+	a.NotError(err).NotNil(r).
+		Equal(r.start, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local))
+
+	for _, spec := range []string{
+		"",
+		"R5",
+		"5/2026-01-01T00:00:00Z/P1D",
+		"Rx/2026-01-01T00:00:00Z/P1D",
+		"R-2/2026-01-01T00:00:00Z/P1D",
+		"R5/2026-13-01T00:00:00Z/P1D",
+		"R5/2026-01-01T00:00:00Z/P1X",
+		"R5/2026-01-01T00:00:00Z/PT0S",
+		"R5/2026-01-01T00:00:00Z/2025-01-01T00:00:00Z",
+		"R5/2026-01-01T00:00:00Z/2026-01-01",
+		"R5/2026-01-01T00:00:00Z/2026",
+		"R5/2026-01-01T00:00:00Z/P1D/P1D",
+	} {
+		r, err := Parse(spec, loc)
+		a.Error(err, spec).Nil(r)
+	}
+}
+
+func TestRepeat_Next(t *testing.T) {
+	a := assert.New(t, false)
+
+	r, err := Parse("R5/2026-01-01T00:00:00Z/P1D", time.UTC)
+	a.NotError(err).NotNil(r)
+	last := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		next := r.Next(last)
+		a.Equal(next, time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC))
+		last = next
+	}
+	a.True(r.Next(last).IsZero())
+
+	// 从中间开始
+	a.Equal(r.Next(time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC)), time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC))
+
+	// 月末
+	r, err = Parse("R/2026-01-31T09:00:00Z/P1M", time.UTC)
+	a.NotError(err).NotNil(r)
+	next := r.Next(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC))
+	next = r.Next(next)
+	a.Equal(next, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC))
+	a.Equal(r.Next(time.Date(2036, 2, 1, 0, 0, 0, 0, time.UTC)), time.Date(2036, 3, 2, 9, 0, 0, 0, time.UTC))
+
+	// 夏令时
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+	r, err = Parse("R/2026-03-07T09:00:00/P1D", ny)
+	a.NotError(err).NotNil(r)
+	next = r.Next(time.Date(2026, 3, 7, 9, 0, 0, 0, ny))
+	a.Equal(next, time.Date(2026, 3, 8, 9, 0, 0, 0, ny))
+	next = r.Next(next)
+	a.Equal(next, time.Date(2026, 3, 9, 9, 0, 0, 0, ny))
+
+	// start/end
+	r, err = Parse("R2/2026-01-01T09:00:00Z/2026-01-01T17:00:00Z", time.UTC)
+	a.NotError(err).NotNil(r)
+	next = r.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 1, 1, 17, 0, 0, 0, time.UTC))
+	a.True(r.Next(next).IsZero())
+
+	// New
+	r, err = New(-1, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Duration{Weeks: 2})
+	a.NotError(err).NotNil(r)
+	a.Equal(r.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	r, err = New(-1, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Duration{Days: -1})
+	a.Error(err).Nil(r)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := schedulers.Parse("repeat:R2/2026-01-01T00:00:00Z/P1D", time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "repeat:R2/2026-01-01T00:00:00Z/P1D").
+		Equal(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	s, err = schedulers.Parse("repeat:R2/2026-01-01T00:00:00Z", time.UTC)
+	a.Error(err).Nil(s)
+}
@@ -0,0 +1,441 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package rrule
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	maxYear = 9999
+
+	// 允许连续为空的周期数量
+	//
+	// 小于 Daily 的频率会整天、整小时或整分钟地跳过不符合要求的周期，
+	// 所以该值只需要能容纳数年的周期即可，比如仅匹配 2 月 29 日的规则。
+	maxEmpty = 1 << 16
+)
+
+// 按顺序生成 RRULE 的所有时间点
+//
+// 所有的计算都基于 DTSTART 所在时区的本地时间，为了避免夏令时的影响，
+// 本地时间以 UTC 时区的 [time.Time] 表示，生成结果时再转换回 DTSTART 的时区。
+type iterator struct {
+	r     *Rule
+	start time.Time // DTSTART
+	loc   *time.Location
+
+	interval int
+	period   time.Time // 当前周期的起始时间，本地时间。
+
+	// 经过默认值处理之后的各个字段
+	byMonth, byMonthDay, bySecond, byMinute, byHour []int
+	byDay                                           []Weekday
+
+	buf   []time.Time
+	count int
+	empty int       // 连续为空的周期数量
+	last  time.Time // 最后一次调用 after 时的参数
+	done  bool
+}
+
+// 返回 DTSTART 为 start 时，在 last 之后的第一个时间点
+//
+// 仅适用于没有 COUNT 的规则，带 COUNT 的规则需要从 DTSTART 开始计数，
+// 应该由调用方保存 [iterator] 的状态。
+func (r *Rule) next(start, last time.Time) time.Time {
+	it := newIterator(r, start)
+	it.seek(last)
+	return it.after(last)
+}
+
+func newIterator(r *Rule, start time.Time) *iterator {
+	it := &iterator{
+		r:          r,
+		start:      start,
+		loc:        start.Location(),
+		interval:   max(r.Interval, 1),
+		byMonth:    r.ByMonth,
+		byMonthDay: r.ByMonthDay,
+		byDay:      r.ByDay,
+		bySecond:   r.BySecond,
+		byMinute:   r.ByMinute,
+		byHour:     r.ByHour,
+	}
+	wall := localTime(start)
+
+	if len(r.ByWeekNo) == 0 && len(r.ByYearDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		switch r.Freq {
+		case Yearly:
+			if len(it.byMonth) == 0 {
+				it.byMonth = []int{int(wall.Month())}
+			}
+			it.byMonthDay = []int{wall.Day()}
+		case Monthly:
+			it.byMonthDay = []int{wall.Day()}
+		case Weekly:
+			it.byDay = []Weekday{{Day: wall.Weekday()}}
+		}
+	}
+
+	if len(it.byHour) == 0 && r.Freq > Hourly {
+		it.byHour = []int{wall.Hour()}
+	}
+	if len(it.byMinute) == 0 && r.Freq > Minutely {
+		it.byMinute = []int{wall.Minute()}
+	}
+	if len(it.bySecond) == 0 && r.Freq > Secondly {
+		it.bySecond = []int{wall.Second()}
+	}
+
+	it.period = it.periodOf(wall)
+
+	// DTSTART 始终是第一个时间点，且计入 COUNT。
+	it.buf = []time.Time{start}
+	it.count = 1
+	it.done = r.Count == 1
+	return it
+}
+
+// 将 t 转换为以 UTC 表示的本地时间
+func localTime(t time.Time) time.Time {
+	y, m, d := t.Date()
+	h, mi, s := t.Clock()
+	return time.Date(y, m, d, h, mi, s, 0, time.UTC)
+}
+
+func (it *iterator) unit() time.Duration {
+	switch it.r.Freq {
+	case Hourly:
+		return time.Hour
+	case Minutely:
+		return time.Minute
+	default:
+		return time.Second
+	}
+}
+
+// 返回 wall 所在周期的起始时间
+func (it *iterator) periodOf(wall time.Time) time.Time {
+	y, m, d := wall.Date()
+	switch it.r.Freq {
+	case Yearly:
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case Monthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	case Weekly:
+		offset := (int(wall.Weekday()) - int(it.r.WeekStart) + 7) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, time.UTC)
+	case Daily:
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	default:
+		return wall.Truncate(it.unit())
+	}
+}
+
+// 将当前周期向后移动 n 个间隔
+func (it *iterator) advance(n int) {
+	n *= it.interval
+	switch it.r.Freq {
+	case Yearly:
+		it.period = it.period.AddDate(n, 0, 0)
+	case Monthly:
+		it.period = it.period.AddDate(0, n, 0)
+	case Weekly:
+		it.period = it.period.AddDate(0, 0, 7*n)
+	case Daily:
+		it.period = it.period.AddDate(0, 0, n)
+	default:
+		it.period = it.period.Add(time.Duration(n) * it.unit())
+	}
+}
+
+// 跳过 t 之前的周期
+//
+// 仅适用于小于 Daily 的频率。
+func (it *iterator) skip(t time.Time) {
+	step := time.Duration(it.interval) * it.unit()
+	it.advance(int((t.Sub(it.period) + step - 1) / step))
+}
+
+// 跳过 last 所在周期之前的周期
+//
+// 仅适用于没有 COUNT 的规则。
+func (it *iterator) seek(last time.Time) {
+	if !last.After(it.start) {
+		return
+	}
+
+	target := it.periodOf(localTime(last.In(it.loc)))
+	var n int
+	switch it.r.Freq {
+	case Yearly:
+		n = target.Year() - it.period.Year()
+	case Monthly:
+		n = (target.Year()-it.period.Year())*12 + int(target.Month()-it.period.Month())
+	case Weekly:
+		n = int(target.Sub(it.period) / (7 * 24 * time.Hour))
+	case Daily:
+		n = int(target.Sub(it.period) / (24 * time.Hour))
+	default:
+		n = int(target.Sub(it.period) / it.unit())
+	}
+
+	// 多退一个周期，防止因为时区转换而遗漏。
+	if n = n/it.interval - 1; n > 0 {
+		it.advance(n)
+	}
+}
+
+// 返回 last 之后的第一个时间点
+//
+// 不晚于 last 的时间点会被丢弃，所以 last 不能早于上一次调用时的值。
+func (it *iterator) after(last time.Time) time.Time {
+	it.last = last
+	for {
+		for len(it.buf) == 0 {
+			if it.done {
+				return time.Time{}
+			}
+			it.fill()
+		}
+
+		if t := it.buf[0]; t.After(last) {
+			return t
+		}
+		it.buf = it.buf[1:]
+	}
+}
+
+// 计算当前周期的所有时间点，并移至下一个周期。
+func (it *iterator) fill() {
+	if it.period.Year() > maxYear || it.empty > maxEmpty ||
+		(!it.r.Until.IsZero() && it.toTime(it.period).After(it.r.Until)) {
+		it.done = true
+		return
+	}
+
+	set := it.expand()
+	if len(set) == 0 {
+		it.empty++
+	} else {
+		it.empty = 0
+	}
+
+	for _, wall := range set {
+		t := it.toTime(wall)
+		if !t.After(it.start) { // DTSTART 已经在 newIterator 中添加
+			continue
+		}
+		if !it.r.Until.IsZero() && t.After(it.r.Until) {
+			it.done = true
+			return
+		}
+
+		it.buf = append(it.buf, t)
+		if it.r.Count > 0 {
+			if it.count++; it.count >= it.r.Count {
+				it.done = true
+				return
+			}
+		}
+	}
+}
+
+func (it *iterator) toTime(wall time.Time) time.Time {
+	y, m, d := wall.Date()
+	h, mi, s := wall.Clock()
+	return time.Date(y, m, d, h, mi, s, 0, it.loc)
+}
+
+// 展开当前周期，并将周期移至下一个。
+func (it *iterator) expand() []time.Time {
+	period := it.period
+
+	if it.r.Freq < Daily {
+		// 整天、整小时或整分钟都不符合要求的，直接跳过。
+		h, mi, s := period.Clock()
+		switch {
+		case !it.matchDay(period):
+			y, m, d := period.Date()
+			it.skip(time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC))
+		case !match(it.byHour, h):
+			it.skip(period.Truncate(time.Hour).Add(time.Hour))
+		case it.r.Freq <= Minutely && !match(it.byMinute, mi):
+			it.skip(period.Truncate(time.Minute).Add(time.Minute))
+		case it.r.Freq <= Secondly && !match(it.bySecond, s):
+			it.advance(1)
+		default:
+			it.advance(1)
+			return it.setPos(it.times(period))
+		}
+		return nil
+	}
+	it.advance(1)
+
+	var days []time.Time
+	switch it.r.Freq {
+	case Yearly:
+		days = daysBetween(period, period.AddDate(1, 0, 0))
+	case Monthly:
+		days = daysBetween(period, period.AddDate(0, 1, 0))
+	case Weekly:
+		days = daysBetween(period, period.AddDate(0, 0, 7))
+	default:
+		days = []time.Time{period}
+	}
+
+	set := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		if it.matchDay(d) {
+			set = append(set, it.times(d)...)
+		}
+	}
+	return it.setPos(set)
+}
+
+// 根据 BYHOUR、BYMINUTE 和 BYSECOND 生成 day 中的所有时间点
+//
+// 对于小于 Daily 的频率，这些字段的默认值都是 day 本身的值。
+func (it *iterator) times(day time.Time) []time.Time {
+	y, m, d := day.Date()
+	h, mi, s := day.Clock()
+
+	hours := it.byHour
+	if it.r.Freq <= Hourly {
+		hours = []int{h}
+	}
+	minutes := it.byMinute
+	if it.r.Freq <= Minutely {
+		minutes = []int{mi}
+	}
+	seconds := it.bySecond
+	if it.r.Freq <= Secondly {
+		seconds = []int{s}
+	}
+
+	ret := make([]time.Time, 0, len(hours)*len(minutes)*len(seconds))
+	for _, hh := range hours {
+		for _, mm := range minutes {
+			for _, ss := range seconds {
+				ret = append(ret, time.Date(y, m, d, hh, mm, ss, 0, time.UTC))
+			}
+		}
+	}
+	slices.SortFunc(ret, time.Time.Compare)
+	return slices.CompactFunc(ret, time.Time.Equal)
+}
+
+// 根据 BYSETPOS 从 set 中选择元素
+func (it *iterator) setPos(set []time.Time) []time.Time {
+	if len(it.r.BySetPos) == 0 || len(set) == 0 {
+		return set
+	}
+
+	ret := make([]time.Time, 0, len(it.r.BySetPos))
+	for _, pos := range it.r.BySetPos {
+		if pos > 0 && pos <= len(set) {
+			ret = append(ret, set[pos-1])
+		} else if pos < 0 && -pos <= len(set) {
+			ret = append(ret, set[len(set)+pos])
+		}
+	}
+	slices.SortFunc(ret, time.Time.Compare)
+	return slices.CompactFunc(ret, time.Time.Equal)
+}
+
+// day 是否符合 BYMONTH、BYWEEKNO、BYYEARDAY、BYMONTHDAY 和 BYDAY 的要求
+func (it *iterator) matchDay(day time.Time) bool {
+	y, m, d := day.Date()
+
+	if !match(it.byMonth, int(m)) {
+		return false
+	}
+
+	if len(it.r.ByWeekNo) > 0 {
+		weekYear, week := weekNumber(day, it.r.WeekStart)
+		weeks := weeksInYear(weekYear, it.r.WeekStart)
+		if !slices.Contains(it.r.ByWeekNo, week) && !slices.Contains(it.r.ByWeekNo, week-weeks-1) {
+			return false
+		}
+	}
+
+	yearDays := daysIn(y)
+	if len(it.r.ByYearDay) > 0 {
+		yd := day.YearDay()
+		if !slices.Contains(it.r.ByYearDay, yd) && !slices.Contains(it.r.ByYearDay, yd-yearDays-1) {
+			return false
+		}
+	}
+
+	monthDays := daysInMonth(y, m)
+	if len(it.byMonthDay) > 0 {
+		if !slices.Contains(it.byMonthDay, d) && !slices.Contains(it.byMonthDay, d-monthDays-1) {
+			return false
+		}
+	}
+
+	if len(it.byDay) > 0 {
+		// 带序号的 BYDAY，在 MONTHLY 或是指定了 BYMONTH 的 YEARLY 中表示月中的第几个，否则为年中的第几个。
+		index, size := day.YearDay(), yearDays
+		if it.r.Freq == Monthly || (it.r.Freq == Yearly && len(it.r.ByMonth) > 0) {
+			index, size = d, monthDays
+		}
+		nth := (index-1)/7 + 1
+		nthLast := -((size-index)/7 + 1)
+
+		return slices.ContainsFunc(it.byDay, func(w Weekday) bool {
+			return w.Day == day.Weekday() && (w.N == 0 || w.N == nth || w.N == nthLast)
+		})
+	}
+
+	return true
+}
+
+func match(list []int, v int) bool { return len(list) == 0 || slices.Contains(list, v) }
+
+// 返回 [start,end) 之间的每一天
+func daysBetween(start, end time.Time) []time.Time {
+	ret := make([]time.Time, 0, 366)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+func daysIn(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// 第一周的起始日期
+//
+// 第一周是指至少有四天在该年中的周，即包含 1 月 4 日的那一周。
+func firstWeek(year int, wkst time.Weekday) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	return jan4.AddDate(0, 0, -((int(jan4.Weekday()) - int(wkst) + 7) % 7))
+}
+
+func weeksInYear(year int, wkst time.Weekday) int {
+	return int(firstWeek(year+1, wkst).Sub(firstWeek(year, wkst)) / (7 * 24 * time.Hour))
+}
+
+// 计算 day 所在的周数
+//
+// 返回的 year 为该周所属的年份，可能与 day 的年份不同。
+func weekNumber(day time.Time, wkst time.Weekday) (year, week int) {
+	year = day.Year()
+	if next := firstWeek(year+1, wkst); !day.Before(next) {
+		year++
+	} else if day.Before(firstWeek(year, wkst)) {
+		year--
+	}
+
+	return year, int(day.Sub(firstWeek(year, wkst))/(7*24*time.Hour)) + 1
+}
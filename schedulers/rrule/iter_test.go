@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package rrule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+)
+
+// RFC 5545 3.8.5.3 中的示例
+func TestSet_Next_rfc5545(t *testing.T) {
+	a := assert.New(t, false)
+
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+
+	type test struct {
+		text  string
+		times []string // DTSTART 之后的时间点，以 America/New_York 时区表示。
+		end   bool     // 在 times 之后是否已经终结
+	}
+
+	data := []*test{
+		{ // Daily for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;COUNT=10",
+			times: []string{"19970902T090000", "19970903T090000", "19970904T090000", "19970905T090000", "19970906T090000", "19970907T090000", "19970908T090000", "19970909T090000", "19970910T090000", "19970911T090000"},
+			end:   true,
+		},
+		{ // Every other day - forever
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;INTERVAL=2",
+			times: []string{"19970902T090000", "19970904T090000", "19970906T090000", "19970908T090000"},
+		},
+		{ // Every 10 days, 5 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;INTERVAL=10;COUNT=5",
+			times: []string{"19970902T090000", "19970912T090000", "19970922T090000", "19971002T090000", "19971012T090000"},
+			end:   true,
+		},
+		{ // Weekly for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=WEEKLY;COUNT=10",
+			times: []string{"19970902T090000", "19970909T090000", "19970916T090000", "19970923T090000", "19970930T090000", "19971007T090000", "19971014T090000", "19971021T090000", "19971028T090000", "19971104T090000"},
+			end:   true,
+		},
+		{ // Every other week - forever
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=WEEKLY;INTERVAL=2;WKST=SU",
+			times: []string{"19970902T090000", "19970916T090000", "19970930T090000", "19971014T090000", "19971028T090000", "19971111T090000", "19971125T090000", "19971209T090000", "19971223T090000", "19980106T090000"},
+		},
+		{ // Weekly on Tuesday and Thursday for five weeks
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			times: []string{"19970902T090000", "19970904T090000", "19970909T090000", "19970911T090000", "19970916T090000", "19970918T090000", "19970923T090000", "19970925T090000", "19970930T090000", "19971002T090000"},
+			end:   true,
+		},
+		{ // Every other week on Monday, Wednesday, and Friday until December 24, 1997
+			text: "DTSTART;TZID=America/New_York:19970901T090000 RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR",
+			times: []string{"19970901T090000", "19970903T090000", "19970905T090000", "19970915T090000", "19970917T090000", "19970919T090000", "19970929T090000",
+				"19971001T090000", "19971003T090000", "19971013T090000", "19971015T090000", "19971017T090000", "19971027T090000", "19971029T090000", "19971031T090000",
+				"19971110T090000", "19971112T090000", "19971114T090000", "19971124T090000", "19971126T090000", "19971128T090000",
+				"19971208T090000", "19971210T090000", "19971212T090000", "19971222T090000"},
+			end: true,
+		},
+		{ // Every other week on Tuesday and Thursday, for 8 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			times: []string{"19970902T090000", "19970904T090000", "19970916T090000", "19970918T090000", "19970930T090000", "19971002T090000", "19971014T090000", "19971016T090000"},
+			end:   true,
+		},
+		{ // Monthly on the first Friday for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970905T090000 RRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			times: []string{"19970905T090000", "19971003T090000", "19971107T090000", "19971205T090000", "19980102T090000", "19980206T090000", "19980306T090000", "19980403T090000", "19980501T090000", "19980605T090000"},
+			end:   true,
+		},
+		{ // Every other month on the first and last Sunday of the month for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970907T090000 RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			times: []string{"19970907T090000", "19970928T090000", "19971102T090000", "19971130T090000", "19980104T090000", "19980125T090000", "19980301T090000", "19980329T090000", "19980503T090000", "19980531T090000"},
+			end:   true,
+		},
+		{ // Monthly on the second-to-last Monday of the month for 6 months
+			text:  "DTSTART;TZID=America/New_York:19970922T090000 RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			times: []string{"19970922T090000", "19971020T090000", "19971117T090000", "19971222T090000", "19980119T090000", "19980216T090000"},
+			end:   true,
+		},
+		{ // Monthly on the third-to-the-last day of the month, forever
+			text:  "DTSTART;TZID=America/New_York:19970928T090000 RRULE:FREQ=MONTHLY;BYMONTHDAY=-3",
+			times: []string{"19970928T090000", "19971029T090000", "19971128T090000", "19971229T090000", "19980129T090000", "19980226T090000"},
+		},
+		{ // Monthly on the first and last day of the month for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970930T090000 RRULE:FREQ=MONTHLY;COUNT=10;BYMONTHDAY=1,-1",
+			times: []string{"19970930T090000", "19971001T090000", "19971031T090000", "19971101T090000", "19971130T090000", "19971201T090000", "19971231T090000", "19980101T090000", "19980131T090000", "19980201T090000"},
+			end:   true,
+		},
+		{ // Every 18 months on the 10th thru 15th of the month for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970910T090000 RRULE:FREQ=MONTHLY;INTERVAL=18;COUNT=10;BYMONTHDAY=10,11,12,13,14,15",
+			times: []string{"19970910T090000", "19970911T090000", "19970912T090000", "19970913T090000", "19970914T090000", "19970915T090000", "19990310T090000", "19990311T090000", "19990312T090000", "19990313T090000"},
+			end:   true,
+		},
+		{ // Every Tuesday, every other month
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=TU",
+			times: []string{"19970902T090000", "19970909T090000", "19970916T090000", "19970923T090000", "19970930T090000", "19971104T090000", "19971111T090000", "19971118T090000", "19971125T090000", "19980106T090000"},
+		},
+		{ // Yearly in June and July for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970610T090000 RRULE:FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			times: []string{"19970610T090000", "19970710T090000", "19980610T090000", "19980710T090000", "19990610T090000", "19990710T090000", "20000610T090000", "20000710T090000", "20010610T090000", "20010710T090000"},
+			end:   true,
+		},
+		{ // Every other year on January, February, and March for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970310T090000 RRULE:FREQ=YEARLY;INTERVAL=2;COUNT=10;BYMONTH=1,2,3",
+			times: []string{"19970310T090000", "19990110T090000", "19990210T090000", "19990310T090000", "20010110T090000", "20010210T090000", "20010310T090000", "20030110T090000", "20030210T090000", "20030310T090000"},
+			end:   true,
+		},
+		{ // Every third year on the 1st, 100th, and 200th day for 10 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970101T090000 RRULE:FREQ=YEARLY;INTERVAL=3;COUNT=10;BYYEARDAY=1,100,200",
+			times: []string{"19970101T090000", "19970410T090000", "19970719T090000", "20000101T090000", "20000409T090000", "20000718T090000", "20030101T090000", "20030410T090000", "20030719T090000", "20060101T090000"},
+			end:   true,
+		},
+		{ // Every 20th Monday of the year, forever
+			text:  "DTSTART;TZID=America/New_York:19970519T090000 RRULE:FREQ=YEARLY;BYDAY=20MO",
+			times: []string{"19970519T090000", "19980518T090000", "19990517T090000"},
+		},
+		{ // Monday of week number 20 (where the default start of the week is Monday), forever
+			text:  "DTSTART;TZID=America/New_York:19970512T090000 RRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO",
+			times: []string{"19970512T090000", "19980511T090000", "19990517T090000"},
+		},
+		{ // Every Thursday in March, forever
+			text:  "DTSTART;TZID=America/New_York:19970313T090000 RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			times: []string{"19970313T090000", "19970320T090000", "19970327T090000", "19980305T090000", "19980312T090000", "19980319T090000", "19980326T090000", "19990304T090000", "19990311T090000", "19990318T090000", "19990325T090000"},
+		},
+		{ // Every Friday the 13th, forever
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 EXDATE;TZID=America/New_York:19970902T090000 RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			times: []string{"19980213T090000", "19980313T090000", "19981113T090000", "19990813T090000", "20001013T090000"},
+		},
+		{ // The first Saturday that follows the first Sunday of the month, forever
+			text:  "DTSTART;TZID=America/New_York:19970913T090000 RRULE:FREQ=MONTHLY;BYDAY=SA;BYMONTHDAY=7,8,9,10,11,12,13",
+			times: []string{"19970913T090000", "19971011T090000", "19971108T090000", "19971213T090000", "19980110T090000", "19980207T090000", "19980307T090000", "19980411T090000", "19980509T090000", "19980613T090000"},
+		},
+		{ // Every 4 years, the first Tuesday after a Monday in November, forever (U.S. Presidential Election day)
+			text:  "DTSTART;TZID=America/New_York:19961105T090000 RRULE:FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			times: []string{"19961105T090000", "20001107T090000", "20041102T090000"},
+		},
+		{ // The third instance into the month of one of Tuesday, Wednesday, or Thursday, for the next 3 months
+			text:  "DTSTART;TZID=America/New_York:19970904T090000 RRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			times: []string{"19970904T090000", "19971007T090000", "19971106T090000"},
+			end:   true,
+		},
+		{ // The second-to-last weekday of the month
+			text:  "DTSTART;TZID=America/New_York:19970929T090000 RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2",
+			times: []string{"19970929T090000", "19971030T090000", "19971127T090000", "19971230T090000", "19980129T090000", "19980226T090000", "19980330T090000"},
+		},
+		{ // Every 3 hours from 9:00 AM to 5:00 PM on a specific day
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T210000Z",
+			times: []string{"19970902T090000", "19970902T120000", "19970902T150000"},
+			end:   true,
+		},
+		{ // Every 15 minutes for 6 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=MINUTELY;INTERVAL=15;COUNT=6",
+			times: []string{"19970902T090000", "19970902T091500", "19970902T093000", "19970902T094500", "19970902T100000", "19970902T101500"},
+			end:   true,
+		},
+		{ // Every hour and a half for 4 occurrences
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=MINUTELY;INTERVAL=90;COUNT=4",
+			times: []string{"19970902T090000", "19970902T103000", "19970902T120000", "19970902T133000"},
+			end:   true,
+		},
+		{ // Every 20 minutes from 9:00 AM to 4:40 PM every day
+			text: "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;BYHOUR=9,10,11,12,13,14,15,16;BYMINUTE=0,20,40",
+			times: []string{"19970902T090000", "19970902T092000", "19970902T094000", "19970902T100000", "19970902T102000", "19970902T104000", "19970902T110000", "19970902T112000", "19970902T114000",
+				"19970902T120000", "19970902T122000", "19970902T124000", "19970902T130000", "19970902T132000", "19970902T134000", "19970902T140000", "19970902T142000", "19970902T144000",
+				"19970902T150000", "19970902T152000", "19970902T154000", "19970902T160000", "19970902T162000", "19970902T164000", "19970903T090000"},
+		},
+		{ // 同上，以 MINUTELY 的方式表示
+			text:  "DTSTART;TZID=America/New_York:19970902T160000 RRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16",
+			times: []string{"19970902T160000", "19970902T162000", "19970902T164000", "19970903T090000", "19970903T092000"},
+		},
+		{ // An example where the days generated makes a difference because of WKST
+			text:  "DTSTART;TZID=America/New_York:19970805T090000 RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			times: []string{"19970805T090000", "19970810T090000", "19970819T090000", "19970824T090000"},
+			end:   true,
+		},
+		{ // changing only WKST from MO to SU, yields different results
+			text:  "DTSTART;TZID=America/New_York:19970805T090000 RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			times: []string{"19970805T090000", "19970817T090000", "19970819T090000", "19970831T090000"},
+			end:   true,
+		},
+		{ // An example where an invalid date (i.e., February 30) is ignored
+			text:  "DTSTART;TZID=America/New_York:20070115T090000 RRULE:FREQ=MONTHLY;BYMONTHDAY=15,30;COUNT=5",
+			times: []string{"20070115T090000", "20070130T090000", "20070215T090000", "20070315T090000", "20070330T090000"},
+			end:   true,
+		},
+		{ // RDATE 与 EXDATE
+			text:  "DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;COUNT=3 RDATE;TZID=America/New_York:19970903T120000,19970910T090000 EXDATE;TZID=America/New_York:19970904T090000",
+			times: []string{"19970902T090000", "19970903T090000", "19970903T120000", "19970910T090000"},
+			end:   true,
+		},
+	}
+
+	for _, item := range data {
+		s, err := Parse(item.text, nil)
+		a.NotError(err, item.text).NotNil(s)
+
+		last := s.DTStart.Add(-time.Second)
+		for _, v := range item.times {
+			want, err := time.ParseInLocation(dateTimeLayout, v, ny)
+			a.NotError(err)
+
+			next := s.Next(last)
+			a.Equal(next, want, "%s: %s != %s", item.text, next, want)
+			a.Equal(s.Next(last), want) // 相同的参数返回相同的值
+			last = next
+		}
+
+		if item.end {
+			a.True(s.Next(last).IsZero(), item.text)
+		}
+	}
+}
+
+func TestSet_Next_until(t *testing.T) {
+	a := assert.New(t, false)
+
+	// Daily until December 24, 1997
+	s, err := Parse("DTSTART;TZID=America/New_York:19970902T090000 RRULE:FREQ=DAILY;UNTIL=19971224T000000Z", nil)
+	a.NotError(err).NotNil(s)
+	var list []time.Time
+	for next := s.Next(s.DTStart.Add(-time.Second)); !next.IsZero(); next = s.Next(next) {
+		list = append(list, next)
+	}
+	a.Length(list, 113).
+		Equal(list[len(list)-1].Format(dateTimeLayout), "19971223T090000")
+
+	// Everyday in January, for 3 years
+	for _, text := range []string{
+		"DTSTART;TZID=America/New_York:19980101T090000 RRULE:FREQ=YEARLY;UNTIL=20000131T140000Z;BYMONTH=1;BYDAY=SU,MO,TU,WE,TH,FR,SA",
+		"DTSTART;TZID=America/New_York:19980101T090000 RRULE:FREQ=DAILY;UNTIL=20000131T140000Z;BYMONTH=1",
+	} {
+		s, err := Parse(text, nil)
+		a.NotError(err).NotNil(s)
+		list = list[:0]
+		for next := s.Next(s.DTStart.Add(-time.Second)); !next.IsZero(); next = s.Next(next) {
+			list = append(list, next)
+		}
+		a.Length(list, 93, text).
+			Equal(list[31].Format(dateTimeLayout), "19990101T090000").
+			Equal(list[92].Format(dateTimeLayout), "20000131T090000")
+	}
+
+	// 夏令时
+	s, err = Parse("DTSTART;TZID=America/New_York:19971025T090000 RRULE:FREQ=DAILY;COUNT=3", nil)
+	a.NotError(err).NotNil(s)
+	next := s.Next(s.DTStart)
+	a.Equal(next.Format(dateTimeLayout+"-07:00"), "19971026T090000-05:00")
+}
+
+func TestSet_Next_seek(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 没有 COUNT 时，从 last 所在的周期开始计算。
+	s, err := Parse("DTSTART:20000101T000000Z RRULE:FREQ=SECONDLY;INTERVAL=7", nil)
+	a.NotError(err).NotNil(s)
+	last := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := s.Next(last)
+	a.True(next.After(last)).
+		True(next.Sub(last) <= 7*time.Second).
+		Equal(next.Sub(s.DTStart)%(7*time.Second), 0)
+
+	s, err = Parse("DTSTART:20000101T000000Z RRULE:FREQ=WEEKLY;INTERVAL=3;BYDAY=MO,FR", nil)
+	a.NotError(err).NotNil(s)
+	next = s.Next(last)
+	a.Equal(next, time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)) // 2025-12-29 所在的周不在间隔之内
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC))
+
+	// 永远不会发生的时间
+	s, err = Parse("DTSTART:20000101T000000Z RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", nil)
+	a.NotError(err).NotNil(s)
+	a.True(s.Next(s.DTStart).IsZero())
+}
+
+func TestSet_Next_skip(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 从当天 09 点之后开始，需要跳过不符合 BYHOUR 的整小时。
+	for _, freq := range []string{"SECONDLY", "MINUTELY"} {
+		s, err := Parse("DTSTART:20260101T100000Z RRULE:FREQ="+freq+";BYHOUR=9", nil)
+		a.NotError(err).NotNil(s)
+		next := s.Next(s.DTStart)
+		a.Equal(next, time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC), freq)
+	}
+
+	s, err := Parse("DTSTART:20260101T100000Z RRULE:FREQ=SECONDLY;INTERVAL=7;BYHOUR=9;BYMINUTE=30", nil)
+	a.NotError(err).NotNil(s)
+	next := s.Next(s.DTStart)
+	a.Equal(next.Format(dateTimeLayout), "20260102T093002")
+	a.Equal(next.Sub(s.DTStart)%(7*time.Second), 0)
+
+	// 仅在闰年出现的时间
+	s, err = Parse("DTSTART:20260101T000000Z RRULE:FREQ=SECONDLY;BYMONTH=2;BYMONTHDAY=29;BYHOUR=9;BYMINUTE=0;BYSECOND=0", nil)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(s.DTStart), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC))
+}
+
+func TestSet_Next_count(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := Parse("DTSTART:20260101T000000Z RRULE:FREQ=SECONDLY;COUNT=100000", nil)
+	a.NotError(err).NotNil(s)
+	var size int
+	for next := s.Next(s.DTStart.Add(-time.Second)); !next.IsZero(); next = s.Next(next) {
+		size++
+	}
+	a.Equal(size, 100000)
+
+	// last 早于上一次调用时的值
+	a.Equal(s.Next(s.DTStart), s.DTStart.Add(time.Second)).
+		Equal(s.Next(s.DTStart.Add(-time.Hour)), s.DTStart)
+
+	// COUNT 为 1 时仅有 DTSTART
+	s, err = Parse("DTSTART:20260101T000000Z RRULE:FREQ=DAILY;COUNT=1", nil)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(s.DTStart.Add(-time.Second)), s.DTStart).
+		True(s.Next(s.DTStart).IsZero())
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package rrule 实现了 [RFC 5545] 中 RRULE 表达式的 [schedulers.Scheduler] 接口
+//
+// [RFC 5545]: https://datatracker.ietf.org/doc/html/rfc5545#section-3.3.10
+package rrule
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/issue9/localeutil"
+)
+
+// 重复的频率
+const (
+	Secondly Frequency = iota
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var (
+	frequencies = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+	weekdays    = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+)
+
+type (
+	// Frequency 重复的频率
+	Frequency int8
+
+	// Weekday 表示 BYDAY 中的一个元素
+	//
+	// N 为 0 表示所有的 Day，否则表示第 N 个 Day，负数表示从后往前计算。
+	Weekday struct {
+		N   int
+		Day time.Weekday
+	}
+
+	// Rule 表示 RRULE 的值
+	Rule struct {
+		Freq     Frequency
+		Interval int // 为 0 时表示 1
+		Count    int
+		Until    time.Time
+
+		BySecond   []int
+		ByMinute   []int
+		ByHour     []int
+		ByDay      []Weekday
+		ByMonthDay []int
+		ByYearDay  []int
+		ByWeekNo   []int
+		ByMonth    []int
+		BySetPos   []int
+		WeekStart  time.Weekday // 一周的起始日，由 [ParseRule] 解析时默认为 [time.Monday]
+	}
+)
+
+func (f Frequency) String() string {
+	if f < Secondly || f > Yearly {
+		return "<unknown>"
+	}
+	return frequencies[f]
+}
+
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdays[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdays[w.Day]
+}
+
+// ParseRule 解析 RRULE 的值
+//
+// value 为 RRULE 的值部分，比如 FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1;COUNT=10，
+// 不区分大小写。loc 用于解析不带时区信息的 UNTIL 值。
+func ParseRule(value string, loc *time.Location) (*Rule, error) {
+	r := &Rule{WeekStart: time.Monday}
+	var hasFreq bool
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, found := strings.Cut(part, "=")
+		if !found || val == "" {
+			return nil, invalidPart(part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			index := slices.Index(frequencies, strings.ToUpper(val))
+			if index < 0 {
+				return nil, invalidPart(part)
+			}
+			r.Freq = Frequency(index)
+			hasFreq = true
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(val); err != nil || r.Interval < 1 {
+				return nil, invalidPart(part)
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(val); err != nil || r.Count < 1 {
+				return nil, invalidPart(part)
+			}
+		case "UNTIL":
+			if r.Until, err = parseUntil(val, loc); err != nil {
+				return nil, invalidPart(part)
+			}
+		case "BYSECOND":
+			r.BySecond, err = parseInts(val, 0, 60, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseInts(val, 0, 59, false)
+		case "BYHOUR":
+			r.ByHour, err = parseInts(val, 0, 23, false)
+		case "BYDAY":
+			r.ByDay, err = parseWeekdays(val)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(val, 1, 31, true)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseInts(val, 1, 366, true)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseInts(val, 1, 53, true)
+		case "BYMONTH":
+			r.ByMonth, err = parseInts(val, 1, 12, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseInts(val, 1, 366, true)
+		case "WKST":
+			index := slices.Index(weekdays, strings.ToUpper(val))
+			if index < 0 {
+				return nil, invalidPart(part)
+			}
+			r.WeekStart = time.Weekday(index)
+		default:
+			return nil, invalidPart(part)
+		}
+
+		if err != nil {
+			return nil, invalidPart(part)
+		}
+	}
+
+	if !hasFreq {
+		return nil, localeutil.Error("missing %s", "FREQ")
+	}
+
+	if err := r.valid(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// 检测各个字段之间的组合是否合法
+func (r *Rule) valid() error {
+	switch {
+	case r.Freq < Secondly || r.Freq > Yearly:
+		return localeutil.Error("invalid rrule part %s", "FREQ="+r.Freq.String())
+	case r.Interval < 0:
+		return localeutil.Error("invalid rrule part %s", "INTERVAL="+strconv.Itoa(r.Interval))
+	case r.Count < 0:
+		return localeutil.Error("invalid rrule part %s", "COUNT="+strconv.Itoa(r.Count))
+	case r.Count > 0 && !r.Until.IsZero():
+		return localeutil.Error("%s conflicts with %s", "COUNT", "UNTIL")
+	case len(r.ByWeekNo) > 0 && r.Freq != Yearly:
+		return localeutil.Error("%s conflicts with %s", "BYWEEKNO", "FREQ="+r.Freq.String())
+	case len(r.ByYearDay) > 0 && (r.Freq == Daily || r.Freq == Weekly || r.Freq == Monthly):
+		return localeutil.Error("%s conflicts with %s", "BYYEARDAY", "FREQ="+r.Freq.String())
+	case len(r.ByMonthDay) > 0 && r.Freq == Weekly:
+		return localeutil.Error("%s conflicts with %s", "BYMONTHDAY", "FREQ="+r.Freq.String())
+	}
+
+	for _, w := range r.ByDay {
+		if w.N == 0 {
+			continue
+		}
+
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return localeutil.Error("%s conflicts with %s", "BYDAY="+w.String(), "FREQ="+r.Freq.String())
+		}
+		if r.Freq == Yearly && len(r.ByWeekNo) > 0 {
+			return localeutil.Error("%s conflicts with %s", "BYDAY="+w.String(), "BYWEEKNO")
+		}
+	}
+
+	return nil
+}
+
+// String 返回 RRULE 的值
+func (r *Rule) String() string {
+	b := &strings.Builder{}
+	b.WriteString("FREQ=")
+	b.WriteString(r.Freq.String())
+
+	if !r.Until.IsZero() {
+		b.WriteString(";UNTIL=")
+		b.WriteString(formatTime(r.Until))
+	}
+	if r.Count > 0 {
+		b.WriteString(";COUNT=")
+		b.WriteString(strconv.Itoa(r.Count))
+	}
+	if r.Interval > 1 {
+		b.WriteString(";INTERVAL=")
+		b.WriteString(strconv.Itoa(r.Interval))
+	}
+
+	writeInts(b, "BYSECOND", r.BySecond)
+	writeInts(b, "BYMINUTE", r.ByMinute)
+	writeInts(b, "BYHOUR", r.ByHour)
+	if len(r.ByDay) > 0 {
+		b.WriteString(";BYDAY=")
+		for i, w := range r.ByDay {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(w.String())
+		}
+	}
+	writeInts(b, "BYMONTHDAY", r.ByMonthDay)
+	writeInts(b, "BYYEARDAY", r.ByYearDay)
+	writeInts(b, "BYWEEKNO", r.ByWeekNo)
+	writeInts(b, "BYMONTH", r.ByMonth)
+	writeInts(b, "BYSETPOS", r.BySetPos)
+
+	if r.WeekStart != time.Monday {
+		b.WriteString(";WKST=")
+		b.WriteString(weekdays[r.WeekStart])
+	}
+
+	return b.String()
+}
+
+func writeInts(b *strings.Builder, name string, vals []int) {
+	if len(vals) == 0 {
+		return
+	}
+
+	b.WriteByte(';')
+	b.WriteString(name)
+	b.WriteByte('=')
+	for i, v := range vals {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(v))
+	}
+}
+
+// 解析以逗号分隔的数值列表
+//
+// signed 表示是否允许负数，负数的绝对值同样需要在 [min,max] 之间。
+func parseInts(val string, min, max int, signed bool) ([]int, error) {
+	fs := strings.Split(val, ",")
+	ret := make([]int, 0, len(fs))
+	for _, f := range fs {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+
+		abs := n
+		if signed && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max {
+			return nil, localeutil.Error("the value %d out of range [%d,%d]", n, min, max)
+		}
+
+		ret = append(ret, n)
+	}
+	return ret, nil
+}
+
+func parseWeekdays(val string) ([]Weekday, error) {
+	fs := strings.Split(val, ",")
+	ret := make([]Weekday, 0, len(fs))
+	for _, f := range fs {
+		if len(f) < 2 {
+			return nil, invalidPart(f)
+		}
+
+		index := slices.Index(weekdays, strings.ToUpper(f[len(f)-2:]))
+		if index < 0 {
+			return nil, invalidPart(f)
+		}
+
+		w := Weekday{Day: time.Weekday(index)}
+		if n := f[:len(f)-2]; n != "" {
+			var err error
+			if w.N, err = strconv.Atoi(n); err != nil || w.N == 0 || w.N < -53 || w.N > 53 {
+				return nil, invalidPart(f)
+			}
+		}
+		ret = append(ret, w)
+	}
+	return ret, nil
+}
+
+func invalidPart(part string) error { return localeutil.Error("invalid rrule part %s", part) }
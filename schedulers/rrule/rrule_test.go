@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestParseRule(t *testing.T) {
+	a := assert.New(t, false)
+
+	r, err := ParseRule("FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1;COUNT=10", time.UTC)
+	a.NotError(err).NotNil(r).
+		Equal(r.Freq, Monthly).
+		Equal(r.ByDay, []Weekday{{Day: time.Monday}, {Day: time.Tuesday}}).
+		Equal(r.BySetPos, []int{-1}).
+		Equal(r.Count, 10).
+		Equal(r.WeekStart, time.Monday).
+		Equal(r.String(), "FREQ=MONTHLY;COUNT=10;BYDAY=MO,TU;BYSETPOS=-1")
+
+	r, err = ParseRule("freq=yearly;interval=2;until=20261231T000000Z;byweekno=1,-1;byday=mo;wkst=su", time.UTC)
+	a.NotError(err).NotNil(r).
+		Equal(r.Freq, Yearly).
+		Equal(r.Interval, 2).
+		Equal(r.Until, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)).
+		Equal(r.ByWeekNo, []int{1, -1}).
+		Equal(r.WeekStart, time.Sunday).
+		Equal(r.String(), "FREQ=YEARLY;UNTIL=20261231T000000Z;INTERVAL=2;BYDAY=MO;BYWEEKNO=1,-1;WKST=SU")
+
+	// UNTIL 仅有日期
+	loc := time.FixedZone("UTC+8", 8*3600)
+	r, err = ParseRule("FREQ=DAILY;UNTIL=20261231;BYHOUR=9,18;BYMINUTE=30;BYSECOND=0;BYMONTH=1,12;BYMONTHDAY=-1", loc)
+	a.NotError(err).NotNil(r).
+		Equal(r.Until, time.Date(2026, 12, 31, 23, 59, 59, 0, loc)).
+		Equal(r.String(), "FREQ=DAILY;UNTIL=20261231T235959;BYSECOND=0;BYMINUTE=30;BYHOUR=9,18;BYMONTHDAY=-1;BYMONTH=1,12")
+
+	r, err = ParseRule("FREQ=YEARLY;BYYEARDAY=-1,100;BYDAY=20MO,-1FR", loc)
+	a.NotError(err).NotNil(r).
+		Equal(r.ByDay, []Weekday{{N: 20, Day: time.Monday}, {N: -1, Day: time.Friday}}).
+		Equal(r.String(), "FREQ=YEARLY;BYDAY=20MO,-1FR;BYYEARDAY=-1,100")
+
+	for _, v := range []string{
+		"",
+		"COUNT=10",
+		"FREQ=NEVER",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=2026",
+		"FREQ=DAILY;COUNT=1;UNTIL=20261231",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYMINUTE=-1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=-32",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=54MO",
+		"FREQ=MONTHLY;BYDAY=M",
+		"FREQ=MONTHLY;WKST=XX",
+		"FREQ=MONTHLY;BYSETPOS=0",
+		"FREQ=MONTHLY;NOT=1",
+		"FREQ=MONTHLY;BYMONTH",
+		"FREQ=MONTHLY;BYWEEKNO=1",
+		"FREQ=MONTHLY;BYYEARDAY=1",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=YEARLY;BYWEEKNO=1;BYDAY=1MO",
+	} {
+		r, err := ParseRule(v, loc)
+		a.Error(err, v).Nil(r)
+	}
+}
+
+func TestFrequency_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Daily.String(), "DAILY").
+		Equal(Yearly.String(), "YEARLY").
+		Equal(Frequency(100).String(), "<unknown>")
+}
@@ -0,0 +1,334 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package rrule
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func init() {
+	schedulers.Register("rrule", func(spec string, loc *time.Location) (schedulers.Scheduler, error) {
+		s, err := Parse(spec, loc)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+}
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+// Set 由 DTSTART、RRULE、RDATE 和 EXDATE 组成的重复集合
+//
+// DTSTART 本身也是集合中的第一个元素，EXDATE 会从最终的结果中排除。
+type Set struct {
+	DTStart time.Time
+	RRule   *Rule // 可以为空，表示仅由 DTSTART 和 RDATE 组成。
+	RDates  []time.Time
+	ExDates []time.Time
+
+	// 带 COUNT 的 RRULE 只能从 DTSTART 开始计算，
+	// 保存迭代器的状态，避免每次调用 Next 都从头开始。
+	locker sync.Mutex
+	it     *iterator
+}
+
+// Parse 解析包含 DTSTART、RRULE、RDATE 和 EXDATE 的内容
+//
+// 各属性之间以空白字符分隔，比如：
+//
+//	DTSTART;TZID=America/New_York:19970902T090000
+//	RRULE:FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1;COUNT=10
+//	EXDATE;TZID=America/New_York:19970930T090000
+//
+// RRULE: 前缀可以省略，DTSTART 是必须的。
+// loc 表示未指定时区的时间所采用的时区，TZID 中的时区信息从本地的时区数据库中加载。
+//
+// 属性中不能包含空白字符，否则应该使用 [ParseProperties]。
+func Parse(text string, loc *time.Location) (*Set, error) {
+	return ParseProperties(strings.Fields(text), loc)
+}
+
+// ParseProperties 从多个属性中解析 [Set]
+//
+// 与 [Parse] 相同，但是每个元素都是一个完整的属性，
+// 所以参数中可以包含空白字符，比如 iCalendar 文件中的 TZID="W. Europe Standard Time"。
+func ParseProperties(props []string, loc *time.Location) (*Set, error) {
+	s := &Set{}
+
+	for _, line := range props {
+		name, params, value, found := SplitProperty(line)
+		if !found {
+			if strings.HasPrefix(strings.ToUpper(line), "FREQ=") {
+				name, value = "RRULE", line
+			} else {
+				return nil, localeutil.Error("invalid rrule part %s", line)
+			}
+		}
+
+		switch strings.ToUpper(name) {
+		case "DTSTART":
+			t, err := parseTime(params, value, loc)
+			if err != nil {
+				return nil, err
+			}
+			s.DTStart = t
+		case "RRULE":
+			if s.RRule != nil {
+				return nil, localeutil.Error("invalid rrule part %s", line)
+			}
+
+			r, err := ParseRule(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			s.RRule = r
+		case "RDATE":
+			list, err := parseTimes(params, value, loc)
+			if err != nil {
+				return nil, err
+			}
+			s.RDates = append(s.RDates, list...)
+		case "EXDATE":
+			list, err := parseTimes(params, value, loc)
+			if err != nil {
+				return nil, err
+			}
+			s.ExDates = append(s.ExDates, list...)
+		default:
+			return nil, localeutil.Error("invalid rrule part %s", line)
+		}
+	}
+
+	if s.DTStart.IsZero() {
+		return nil, localeutil.Error("missing %s", "DTSTART")
+	}
+
+	slices.SortFunc(s.RDates, time.Time.Compare)
+	return s, nil
+}
+
+// SplitProperty 将 [RFC 5545] 中的属性拆分为名称、参数和值
+//
+// 名称与参数之间以及各参数之间以分号分隔，值之前为冒号，
+// 双引号中的分号和冒号属于参数值的一部分。比如：
+//
+//	DTSTART;TZID="America/New_York":19970902T090000
+//
+// 拆分为 DTSTART、[TZID="America/New_York"] 和 19970902T090000。
+// 参数值中的双引号会保留，不存在值时 ok 为 false。
+//
+// [RFC 5545]: https://datatracker.ietf.org/doc/html/rfc5545#section-3.1
+func SplitProperty(line string) (name string, params []string, value string, ok bool) {
+	var quoted bool
+	begin := 0
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted || (c != ';' && c != ':'):
+		default:
+			if begin == 0 {
+				name = line[:i]
+			} else {
+				params = append(params, line[begin:i])
+			}
+			begin = i + 1
+
+			if c == ':' {
+				return name, params, line[i+1:], true
+			}
+		}
+	}
+	return "", nil, "", false
+}
+
+// String 返回 [Parse] 可解析的内容
+//
+// RDATE 和 EXDATE 中的时间会转换到 DTSTART 的时区。
+func (s *Set) String() string {
+	loc := s.DTStart.Location()
+	params := timeParams(loc)
+
+	b := &strings.Builder{}
+	b.WriteString("DTSTART")
+	b.WriteString(params)
+	b.WriteByte(':')
+	b.WriteString(formatTime(s.DTStart))
+
+	if s.RRule != nil {
+		b.WriteString("\nRRULE:")
+		b.WriteString(s.RRule.String())
+	}
+
+	writeTimes := func(name string, list []time.Time) {
+		if len(list) == 0 {
+			return
+		}
+
+		b.WriteByte('\n')
+		b.WriteString(name)
+		b.WriteString(params)
+		b.WriteByte(':')
+		for i, t := range list {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(formatTime(t.In(loc)))
+		}
+	}
+	writeTimes("RDATE", s.RDates)
+	writeTimes("EXDATE", s.ExDates)
+
+	return b.String()
+}
+
+func (s *Set) Next(last time.Time) time.Time {
+	for {
+		next := s.next(last)
+		if next.IsZero() || !slices.ContainsFunc(s.ExDates, next.Equal) {
+			return next
+		}
+		last = next
+	}
+}
+
+// 不考虑 EXDATE 的情况下，last 之后的第一个时间点。
+func (s *Set) next(last time.Time) time.Time {
+	var next time.Time
+	if s.RRule != nil {
+		next = s.rrule(last)
+	}
+
+	if s.DTStart.After(last) && (next.IsZero() || s.DTStart.Before(next)) {
+		next = s.DTStart
+	}
+
+	for _, t := range s.RDates {
+		if t.After(last) {
+			if next.IsZero() || t.Before(next) {
+				next = t
+			}
+			break
+		}
+	}
+
+	return next
+}
+
+// RRULE 中 last 之后的第一个时间点
+func (s *Set) rrule(last time.Time) time.Time {
+	if s.RRule.Count == 0 {
+		return s.RRule.next(s.DTStart, last)
+	}
+
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if it := s.it; it == nil || it.r != s.RRule || !it.start.Equal(s.DTStart) ||
+		it.loc != s.DTStart.Location() || last.Before(it.last) {
+		s.it = newIterator(s.RRule, s.DTStart)
+	}
+	return s.it.after(last)
+}
+
+func timeParams(loc *time.Location) string {
+	switch loc {
+	case time.UTC, time.Local:
+		return ""
+	default:
+		return ";TZID=" + loc.String()
+	}
+}
+
+// 格式化时间
+//
+// UTC 时间以 Z 结尾，其它的时区则仅输出本地时间。
+func formatTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return t.Format(dateTimeLayout) + "Z"
+	}
+	return t.Format(dateTimeLayout)
+}
+
+// 解析 DTSTART、RDATE 和 EXDATE 中的时间
+//
+// params 为属性中的参数，比如 [TZID=America/New_York VALUE=DATE]。
+func parseTimes(params []string, value string, loc *time.Location) ([]time.Time, error) {
+	fs := strings.Split(value, ",")
+	ret := make([]time.Time, 0, len(fs))
+	for _, f := range fs {
+		t, err := parseTime(params, f, loc)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, t)
+	}
+	return ret, nil
+}
+
+func parseTime(params []string, value string, loc *time.Location) (time.Time, error) {
+	layout := dateTimeLayout
+
+	for _, p := range params {
+		name, val, _ := strings.Cut(p, "=")
+		switch strings.ToUpper(name) {
+		case "TZID":
+			l, err := time.LoadLocation(strings.Trim(val, `"`))
+			if err != nil {
+				return time.Time{}, err
+			}
+			loc = l
+		case "VALUE":
+			switch strings.ToUpper(strings.Trim(val, `"`)) {
+			case "DATE":
+				layout = dateLayout
+			case "DATE-TIME":
+			default:
+				return time.Time{}, localeutil.Error("invalid rrule part %s", p)
+			}
+		}
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		value = strings.TrimSuffix(value, "Z")
+		loc = time.UTC
+	}
+
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, localeutil.Error("invalid date %s", value)
+	}
+	return t, nil
+}
+
+// 解析 UNTIL 的值
+//
+// 如果仅包含日期，表示该日期的最后一秒。
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 23, 59, 59, 0, loc), nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.ParseInLocation(dateTimeLayout, strings.TrimSuffix(value, "Z"), time.UTC)
+	}
+	return time.ParseInLocation(dateTimeLayout, value, loc)
+}
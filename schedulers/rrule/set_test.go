@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var _ schedulers.Scheduler = &Set{}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t, false)
+
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+
+	text := "DTSTART;TZID=America/New_York:19970902T090000\n" +
+		"RRULE:FREQ=MONTHLY;COUNT=10;BYDAY=MO,TU;BYSETPOS=-1\n" +
+		"RDATE;TZID=America/New_York:19970903T090000,19970904T090000\n" +
+		"EXDATE;TZID=America/New_York:19970930T090000"
+	s, err := Parse(text, time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.DTStart, time.Date(1997, 9, 2, 9, 0, 0, 0, ny)).
+		Equal(s.RRule.Freq, Monthly).
+		Length(s.RDates, 2).
+		Length(s.ExDates, 1).
+		Equal(s.String(), text)
+
+	// 省略 RRULE: 前缀，UTC 时间
+	s, err = Parse("DTSTART:20260101T090000Z FREQ=DAILY", time.Local)
+	a.NotError(err).NotNil(s).
+		Equal(s.DTStart, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)).
+		Equal(s.String(), "DTSTART:20260101T090000Z\nRRULE:FREQ=DAILY")
+
+	// 浮动时间与仅日期
+	loc := time.FixedZone("UTC+8", 8*3600)
+	s, err = Parse("DTSTART;VALUE=DATE:20260101 RDATE:20260105T090000", loc)
+	a.NotError(err).NotNil(s).
+		Nil(s.RRule).
+		Equal(s.DTStart, time.Date(2026, 1, 1, 0, 0, 0, 0, loc)).
+		Equal(s.RDates, []time.Time{time.Date(2026, 1, 5, 9, 0, 0, 0, loc)})
+	a.Equal(s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, loc)), s.DTStart).
+		Equal(s.Next(s.DTStart), s.RDates[0]).
+		True(s.Next(s.RDates[0]).IsZero())
+
+	for _, v := range []string{
+		"",
+		"FREQ=DAILY",
+		"DTSTART:2026",
+		"DTSTART;TZID=Not/Exists:20260101T000000",
+		"DTSTART;VALUE=PERIOD:20260101T000000",
+		"DTSTART:20260101T000000 RRULE:FREQ=DAILY RRULE:FREQ=DAILY",
+		"DTSTART:20260101T000000 RRULE:FREQ=NEVER",
+		"DTSTART:20260101T000000 RDATE:2026",
+		"DTSTART:20260101T000000 EXDATE:2026",
+		"DTSTART:20260101T000000 SUMMARY:abc",
+		"DTSTART:20260101T000000 abc",
+	} {
+		s, err := Parse(v, time.UTC)
+		a.Error(err, v).Nil(s)
+	}
+}
+
+func TestSplitProperty(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, item := range []struct {
+		line   string
+		name   string
+		params []string
+		value  string
+		ok     bool
+	}{
+		{line: "RRULE:FREQ=DAILY", name: "RRULE", value: "FREQ=DAILY", ok: true},
+		{line: "DTSTART;TZID=Asia/Shanghai;VALUE=DATE-TIME:20260101T000000", name: "DTSTART", params: []string{"TZID=Asia/Shanghai", "VALUE=DATE-TIME"}, value: "20260101T000000", ok: true},
+		{line: `DTSTART;TZID="China Standard Time":20260101T000000`, name: "DTSTART", params: []string{`TZID="China Standard Time"`}, value: "20260101T000000", ok: true},
+		{line: `SUMMARY;ALTREP="cid:a;b":x:y`, name: "SUMMARY", params: []string{`ALTREP="cid:a;b"`}, value: "x:y", ok: true},
+		{line: "FREQ=DAILY"},
+		{line: `DTSTART;TZID="Asia/Shanghai:20260101T000000`}, // 引号未闭合
+	} {
+		name, params, value, ok := SplitProperty(item.line)
+		a.Equal(ok, item.ok, item.line).
+			Equal(name, item.name, item.line).
+			Equal(params, item.params, item.line).
+			Equal(value, item.value, item.line)
+	}
+}
+
+func TestSet_Next(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 未与 RRULE 同步的 DTSTART 也是第一个时间点，且计入 COUNT。
+	s, err := Parse("DTSTART:20260101T090000Z RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=2", time.UTC)
+	a.NotError(err).NotNil(s)
+
+	next := s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+	a.True(s.Next(next).IsZero())
+
+	// 所有时间都被排除
+	s, err = Parse("DTSTART:20260101T090000Z RRULE:FREQ=DAILY;COUNT=2 EXDATE:20260101T090000Z,20260102T090000Z", time.UTC)
+	a.NotError(err).NotNil(s)
+	a.True(s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := schedulers.Parse("rrule:DTSTART:20260101T090000Z RRULE:FREQ=DAILY;COUNT=2", time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)), time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC))
+
+	s, err = schedulers.Parse("rrule:FREQ=DAILY", time.UTC)
+	a.Error(err).Nil(s)
+}
@@ -18,6 +18,21 @@ type Scheduler interface {
 	Next(last time.Time) time.Time
 }
 
+// Notifier 调度规则在运行过程中可能发生变化的调度器
+//
+// 调度器的使用者，比如 [scheduled.Server]，在规则发生变化之后会重新调用 Next 计算下一次的时间。
+//
+// [scheduled.Server]: https://pkg.go.dev/github.com/issue9/scheduled#Server
+type Notifier interface {
+	Scheduler
+
+	// Notify 注册在调度规则发生变化之后调用的函数
+	//
+	// 返回值用于取消注册。f 不能在持有调度器的锁时调用，
+	// 因为 f 中一般会调用调度器的 Next 方法。
+	Notify(f func()) (cancel func())
+}
+
 type SchedulerFunc func(time.Time) time.Time
 
 func (f SchedulerFunc) Next(last time.Time) time.Time { return f(last) }
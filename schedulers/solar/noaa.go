@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// 2000-01-01 12:00 UTC，即 J2000.0
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// Time 计算 date 所在日期的事件 e 发生的时间
+//
+// date 仅使用其在所在时区中的年月日部分；lat 和 lon 分别为纬度和经度，
+// 北纬和东经为正数。
+// 如果当天不会发生该事件（极昼或是极夜），则返回 false。
+func Time(date time.Time, lat, lon float64, e Event) (time.Time, bool) {
+	y, m, d := date.Date()
+	base := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	// 先以正午估算，再以估算的时间重新计算一次，以提高精度。
+	minutes := 720.0
+	for range 2 {
+		t := base.Add(time.Duration(minutes * float64(time.Minute)))
+		eqTime, decl := position(centuries(t))
+
+		noon := 720 - 4*lon - eqTime
+		if e == Noon {
+			minutes = noon
+			continue
+		}
+
+		ha, ok := hourAngle(lat, decl, e.zenith())
+		if !ok {
+			return time.Time{}, false
+		}
+		if e.morning() {
+			minutes = noon - 4*ha
+		} else {
+			minutes = noon + 4*ha
+		}
+	}
+
+	t := base.Add(time.Duration(math.Round(minutes * float64(time.Minute)))).Truncate(time.Second)
+	return t.In(date.Location()), true
+}
+
+// 从 J2000.0 开始的儒略世纪数
+func centuries(t time.Time) float64 {
+	return t.Sub(j2000).Hours() / 24 / 36525
+}
+
+// 计算时差（分钟）以及太阳赤纬（度）
+func position(t float64) (eqTime, decl float64) {
+	l0 := math.Mod(280.46646+t*(36000.76983+t*0.0003032), 360) // 太阳几何平黄经
+	m := 357.52911 + t*(35999.05029-0.0001537*t)               // 太阳平近点角
+	e := 0.016708634 - t*(0.000042037+0.0000001267*t)          // 地球轨道偏心率
+
+	c := sin(m)*(1.914602-t*(0.004817+0.000014*t)) + sin(2*m)*(0.019993-0.000101*t) + sin(3*m)*0.000289
+	omega := 125.04 - 1934.136*t
+	lambda := l0 + c - 0.00569 - 0.00478*sin(omega) // 太阳视黄经
+
+	obliq := 23 + (26+(21.448-t*(46.815+t*(0.00059-t*0.001813)))/60)/60 + 0.00256*cos(omega) // 修正后的黄赤交角
+	decl = deg(math.Asin(sin(obliq) * sin(lambda)))
+
+	y := math.Pow(math.Tan(rad(obliq/2)), 2)
+	eqTime = 4 * deg(y*sin(2*l0)-2*e*sin(m)+4*e*y*sin(m)*cos(2*l0)-0.5*y*y*sin(4*l0)-1.25*e*e*sin(2*m))
+	return eqTime, decl
+}
+
+// 太阳天顶角为 zenith 时的时角（度）
+func hourAngle(lat, decl, zenith float64) (float64, bool) {
+	v := cos(zenith)/(cos(lat)*cos(decl)) - math.Tan(rad(lat))*math.Tan(rad(decl))
+	if v < -1 || v > 1 {
+		return 0, false
+	}
+	return deg(math.Acos(v)), true
+}
+
+func rad(d float64) float64 { return d * math.Pi / 180 }
+
+func deg(r float64) float64 { return r * 180 / math.Pi }
+
+func sin(d float64) float64 { return math.Sin(rad(d)) }
+
+func cos(d float64) float64 { return math.Cos(rad(d)) }
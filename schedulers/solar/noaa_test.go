@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package solar
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestTime(t *testing.T) {
+	a := assert.New(t, false)
+
+	london, err := time.LoadLocation("Europe/London")
+	a.NotError(err)
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	a.NotError(err)
+
+	data := []*struct {
+		date     time.Time
+		lat, lon float64
+		e        Event
+		want     time.Time
+	}{
+		// 伦敦夏至
+		{date: time.Date(2026, 6, 21, 0, 0, 0, 0, london), lat: 51.5074, lon: -0.1278, e: Sunrise, want: time.Date(2026, 6, 21, 4, 43, 0, 0, london)},
+		{date: time.Date(2026, 6, 21, 0, 0, 0, 0, london), lat: 51.5074, lon: -0.1278, e: Sunset, want: time.Date(2026, 6, 21, 21, 21, 0, 0, london)},
+		{date: time.Date(2026, 6, 21, 0, 0, 0, 0, london), lat: 51.5074, lon: -0.1278, e: Noon, want: time.Date(2026, 6, 21, 13, 2, 0, 0, london)},
+
+		// 格林尼治，时差最大的日期
+		{date: time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC), lat: 51.4769, lon: 0, e: Noon, want: time.Date(2026, 11, 3, 11, 43, 35, 0, time.UTC)},
+
+		// 北京元旦
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: Sunrise, want: time.Date(2026, 1, 1, 7, 36, 0, 0, shanghai)},
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: Sunset, want: time.Date(2026, 1, 1, 17, 1, 0, 0, shanghai)},
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: CivilDawn, want: time.Date(2026, 1, 1, 7, 6, 0, 0, shanghai)},
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: CivilDusk, want: time.Date(2026, 1, 1, 17, 31, 0, 0, shanghai)},
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: NauticalDawn, want: time.Date(2026, 1, 1, 6, 32, 0, 0, shanghai)},
+		{date: time.Date(2026, 1, 1, 0, 0, 0, 0, shanghai), lat: 39.9042, lon: 116.4074, e: AstronomicalDusk, want: time.Date(2026, 1, 1, 18, 37, 0, 0, shanghai)},
+	}
+
+	for _, item := range data {
+		got, ok := Time(item.date, item.lat, item.lon, item.e)
+		a.True(ok, item.e).
+			Equal(got.Location(), item.date.Location()).
+			True(got.Sub(item.want).Abs() <= 2*time.Minute, "%s: got %s want %s", item.e, got, item.want)
+	}
+
+	// 特罗姆瑟的极夜和极昼
+	_, ok := Time(time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553, Sunrise)
+	a.False(ok)
+	_, ok = Time(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553, Sunset)
+	a.False(ok)
+	_, ok = Time(time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553, CivilDawn)
+	a.True(ok)
+	_, ok = Time(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553, Noon)
+	a.True(ok)
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package solar 根据日出、日落等太阳事件执行的定时器
+//
+// 采用 NOAA 的太阳位置算法，无须联网，精度在一分钟左右。
+package solar
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/issue9/localeutil"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// 查找下一次事件时最多查找的天数
+//
+// 极点附近的极昼或是极夜可能持续半年左右。
+const maxDays = 2 * 366
+
+// Event 太阳事件
+type Event int8
+
+const (
+	Sunrise          Event = iota // 日出
+	Sunset                        // 日落
+	Noon                          // 太阳正午
+	CivilDawn                     // 民用晨光始
+	CivilDusk                     // 民用昏影终
+	NauticalDawn                  // 航海晨光始
+	NauticalDusk                  // 航海昏影终
+	AstronomicalDawn              // 天文晨光始
+	AstronomicalDusk              // 天文昏影终
+)
+
+var eventStrings = map[Event]string{
+	Sunrise:          "sunrise",
+	Sunset:           "sunset",
+	Noon:             "noon",
+	CivilDawn:        "civil-dawn",
+	CivilDusk:        "civil-dusk",
+	NauticalDawn:     "nautical-dawn",
+	NauticalDusk:     "nautical-dusk",
+	AstronomicalDawn: "astronomical-dawn",
+	AstronomicalDusk: "astronomical-dusk",
+}
+
+// Solar 在太阳事件发生时执行的定时器
+type Solar struct {
+	lat, lon float64
+	event    Event
+	offset   time.Duration
+	loc      *time.Location
+}
+
+var _ schedulers.Scheduler = &Solar{}
+
+// New 声明 [Solar] 对象
+//
+// lat 和 lon 分别为纬度和经度，北纬和东经为正数；
+// offset 为相对于事件的偏移量，比如日落前半小时可以指定为 -30 分钟；
+// loc 为计算日期时所采用的时区，为 nil 表示 [time.Local]。
+//
+// 在极昼或是极夜期间，不会发生的事件会被跳过。
+func New(lat, lon float64, e Event, offset time.Duration, loc *time.Location) (*Solar, error) {
+	switch {
+	case lat < -90 || lat > 90:
+		return nil, localeutil.Error("invalid value %s", strconv.FormatFloat(lat, 'f', -1, 64))
+	case lon < -180 || lon > 180:
+		return nil, localeutil.Error("invalid value %s", strconv.FormatFloat(lon, 'f', -1, 64))
+	case e < Sunrise || e > AstronomicalDusk:
+		return nil, localeutil.Error("invalid value %s", strconv.Itoa(int(e)))
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &Solar{lat: lat, lon: lon, event: e, offset: offset, loc: loc}, nil
+}
+
+func (s *Solar) Next(last time.Time) time.Time {
+	// 从前一天开始查找，避免时区与经度不一致或是偏移量较大时漏掉事件。
+	date := last.Add(-s.offset).In(s.loc).AddDate(0, 0, -1)
+	for i := 0; i <= maxDays; i++ {
+		d := date.AddDate(0, 0, i)
+		if t, ok := Time(d, s.lat, s.lon, s.event); ok {
+			if t = t.Add(s.offset); t.After(last) {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (e Event) String() string {
+	if s, found := eventStrings[e]; found {
+		return s
+	}
+	return "<unknown>"
+}
+
+// 事件对应的太阳天顶角
+func (e Event) zenith() float64 {
+	switch e {
+	case CivilDawn, CivilDusk:
+		return 96
+	case NauticalDawn, NauticalDusk:
+		return 102
+	case AstronomicalDawn, AstronomicalDusk:
+		return 108
+	default: // 考虑了大气折射和太阳视半径
+		return 90.833
+	}
+}
+
+func (e Event) morning() bool {
+	return e == Sunrise || e == CivilDawn || e == NauticalDawn || e == AstronomicalDawn
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2018-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := New(91, 0, Sunrise, 0, nil)
+	a.Error(err).Nil(s)
+
+	s, err = New(0, -181, Sunrise, 0, nil)
+	a.Error(err).Nil(s)
+
+	s, err = New(0, 0, AstronomicalDusk+1, 0, nil)
+	a.Error(err).Nil(s)
+
+	s, err = New(0, 0, Sunset, 0, nil)
+	a.NotError(err).NotNil(s).Equal(s.loc, time.Local)
+}
+
+func TestSolar_Next(t *testing.T) {
+	a := assert.New(t, false)
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	a.NotError(err)
+
+	s, err := New(39.9042, 116.4074, Sunset, -30*time.Minute, shanghai)
+	a.NotError(err).NotNil(s)
+
+	near := func(got, want time.Time) {
+		t.Helper()
+		a.True(got.Sub(want).Abs() <= 2*time.Minute, "got %s want %s", got, want)
+	}
+
+	// 日落前半小时
+	next := s.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, shanghai))
+	near(next, time.Date(2026, 1, 1, 16, 31, 0, 0, shanghai))
+	next2 := s.Next(next)
+	near(next2, time.Date(2026, 1, 2, 16, 32, 0, 0, shanghai))
+	a.Equal(s.Next(next), next2) // 相同的参数返回相同的值
+
+	// 已经过了当天的时间
+	near(s.Next(time.Date(2026, 1, 1, 16, 40, 0, 0, shanghai)), time.Date(2026, 1, 2, 16, 32, 0, 0, shanghai))
+
+	// 偏移量跨越日期
+	s, err = New(39.9042, 116.4074, Sunrise, -8*time.Hour, shanghai)
+	a.NotError(err).NotNil(s)
+	near(s.Next(time.Date(2026, 1, 1, 9, 0, 0, 0, shanghai)), time.Date(2026, 1, 1, 23, 36, 0, 0, shanghai))
+
+	// 极夜期间跳过
+	s, err = New(69.6492, 18.9553, Sunrise, 0, time.UTC)
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+	a.True(next.After(time.Date(2027, 1, 10, 0, 0, 0, 0, time.UTC)) &&
+		next.Before(time.Date(2027, 1, 20, 0, 0, 0, 0, time.UTC)), next)
+
+	// 极昼期间跳过
+	s, err = New(69.6492, 18.9553, Sunset, 0, time.UTC)
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	a.True(next.After(time.Date(2026, 7, 18, 0, 0, 0, 0, time.UTC)) &&
+		next.Before(time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)), next)
+
+	// 极点处无法计算时角，找不到事件时返回零值。
+	s, err = New(90, 0, AstronomicalDawn, 0, time.UTC)
+	a.NotError(err).NotNil(s)
+	a.True(s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestEvent_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Sunrise.String(), "sunrise").
+		Equal(AstronomicalDusk.String(), "astronomical-dusk").
+		Equal(Event(100).String(), "<unknown>")
+}
@@ -8,18 +8,44 @@ package ticker
 import (
 	"time"
 
+	"github.com/issue9/localeutil"
+
 	"github.com/issue9/scheduled/schedulers"
 )
 
+func init() {
+	schedulers.Register("every", func(spec string, _ *time.Location) (schedulers.Scheduler, error) {
+		d, err := time.ParseDuration(spec)
+		if err != nil {
+			return nil, localeutil.Error("invalid duration %s", spec)
+		}
+		return New(d, false)
+	})
+}
+
 // Tick 声明一个固定时间段的定时任务
 //
+// 与 [New] 相同，但是在参数错误时 panic。
+func Tick(d time.Duration, imm bool) schedulers.Scheduler {
+	if d <= 0 {
+		panic("参数 d 的值必须大于 0")
+	}
+	return tick(d, imm)
+}
+
+// New 声明一个固定时间段的定时任务
+//
+// d 可以小于 1 秒，但必须大于 0，否则返回 [schedulers.InvalidError]；
 // imm 是否立即执行一次任务，如果为 true，
 // 则会在第一次调用 Next 时返回当前时间。
-func Tick(d time.Duration, imm bool) schedulers.Scheduler {
-	if d < time.Second {
-		panic("参数 d 的值必须在 1 秒以上")
+func New(d time.Duration, imm bool) (schedulers.Scheduler, error) {
+	if err := checkDuration(d); err != nil {
+		return nil, err
 	}
+	return tick(d, imm), nil
+}
 
+func tick(d time.Duration, imm bool) schedulers.Scheduler {
 	return schedulers.SchedulerFunc(func(last time.Time) time.Time {
 		if imm {
 			imm = false
@@ -29,3 +55,54 @@ func Tick(d time.Duration, imm bool) schedulers.Scheduler {
 		return last.Add(d)
 	})
 }
+
+// Aligned 声明一个与时钟对齐的固定时间段的定时任务
+//
+// 与 [NewAligned] 相同，但是在参数错误时 panic。
+func Aligned(d time.Duration, loc *time.Location) schedulers.Scheduler {
+	if d <= 0 {
+		panic("参数 d 的值必须大于 0")
+	}
+	return aligned(d, loc)
+}
+
+// NewAligned 声明一个与时钟对齐的固定时间段的定时任务
+//
+// 返回的时间点总是 loc 时区中时钟时间为 d 的整数倍的时间，
+// 比如 d 为 5 分钟，则只会在 00:05、00:10 等时间点执行。
+// 时钟的零点为公元 1 年 1 月 1 日（周一）零时，所以 d 为一周时，对齐到每周一的零时。
+//
+// 对齐是按照 loc 中的时钟计算的，在夏令时切换时，与 cron 的行为相同：
+// 不存在的时间点会顺延，重复的时间段只执行一次。
+//
+// d 必须大于 0，否则返回 [schedulers.InvalidError]；loc 为 nil 表示 [time.Local]。
+func NewAligned(d time.Duration, loc *time.Location) (schedulers.Scheduler, error) {
+	if err := checkDuration(d); err != nil {
+		return nil, err
+	}
+	return aligned(d, loc), nil
+}
+
+func aligned(d time.Duration, loc *time.Location) schedulers.Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return schedulers.SchedulerFunc(func(last time.Time) time.Time {
+		last = last.In(loc)
+		wall := time.Date(last.Year(), last.Month(), last.Day(), last.Hour(), last.Minute(), last.Second(), last.Nanosecond(), time.UTC)
+		for wall = wall.Truncate(d).Add(d); ; wall = wall.Add(d) {
+			next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+			if next.After(last) {
+				return next
+			}
+		}
+	})
+}
+
+func checkDuration(d time.Duration) error {
+	if d <= 0 {
+		return &schedulers.InvalidError{Spec: d.String(), Err: localeutil.Error("invalid duration %s", d.String())}
+	}
+	return nil
+}
@@ -5,23 +5,32 @@
 package ticker
 
 import (
+	"errors"
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/scheduled/schedulers"
 )
 
 func TestTicker(t *testing.T) {
 	a := assert.New(t, false)
 
 	a.PanicString(func() {
-		Tick(300*time.Microsecond, false)
-	}, "参数 d 的值必须在 1 秒以上")
+		Tick(0, false)
+	}, "参数 d 的值必须大于 0")
 
-	s := Tick(5*time.Minute, false)
+	s := Tick(250*time.Millisecond, false)
 	a.NotNil(s)
-
 	now := time.Now()
+	a.Equal(s.Next(now), now.Add(250*time.Millisecond))
+
+	s = Tick(5*time.Minute, false)
+	a.NotNil(s)
+
+	now = time.Now()
 	next1 := s.Next(now)
 	a.Equal(next1.Unix(), now.Add(5*time.Minute).Unix())
 
@@ -43,3 +52,73 @@ func TestTicker(t *testing.T) {
 	next2 = s.Next(next1)
 	a.Equal(next2.Unix(), now.Add(5*time.Minute).Unix())
 }
+
+func TestAligned(t *testing.T) {
+	a := assert.New(t, false)
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	a.PanicString(func() {
+		Aligned(-time.Second, loc)
+	}, "参数 d 的值必须大于 0")
+
+	s := Aligned(5*time.Minute, loc)
+	a.NotNil(s)
+	next := s.Next(time.Date(2026, 1, 1, 9, 3, 12, 0, loc))
+	a.Equal(next, time.Date(2026, 1, 1, 9, 5, 0, 0, loc))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2026, 1, 1, 9, 10, 0, 0, loc))
+	a.Equal(s.Next(time.Date(2026, 1, 1, 1, 3, 12, 0, time.UTC)), time.Date(2026, 1, 1, 9, 5, 0, 0, loc))
+
+	s = Aligned(250*time.Millisecond, loc)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 9, 3, 12, 100, loc)), time.Date(2026, 1, 1, 9, 3, 12, 250*int(time.Millisecond), loc))
+
+	// 以 loc 的零点对齐
+	s = Aligned(24*time.Hour, loc)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 9, 3, 12, 0, loc)), time.Date(2026, 1, 2, 0, 0, 0, 0, loc))
+
+	s = Aligned(7*24*time.Hour, loc)
+	a.Equal(s.Next(time.Date(2026, 1, 1, 9, 3, 12, 0, loc)), time.Date(2026, 1, 5, 0, 0, 0, 0, loc))
+
+	// 夏令时
+	ny, err := time.LoadLocation("America/New_York")
+	a.NotError(err)
+	s = Aligned(24*time.Hour, ny)
+	a.Equal(s.Next(time.Date(2026, 3, 7, 12, 0, 0, 0, ny)), time.Date(2026, 3, 8, 0, 0, 0, 0, ny)).
+		Equal(s.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, ny)), time.Date(2026, 3, 9, 0, 0, 0, 0, ny))
+
+	s = Aligned(time.Hour, ny)
+	a.Equal(s.Next(time.Date(2026, 3, 8, 1, 30, 0, 0, ny)), time.Date(2026, 3, 8, 3, 0, 0, 0, ny))
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := New(0, false)
+	a.Error(err).Nil(s)
+	var target *schedulers.InvalidError
+	a.True(errors.As(err, &target)).Equal(target.Spec, "0s")
+
+	s, err = New(time.Millisecond, false)
+	a.NotError(err).NotNil(s)
+
+	s, err = NewAligned(-time.Second, nil)
+	a.Error(err).Nil(s).True(errors.As(err, &target))
+
+	s, err = NewAligned(time.Hour, nil)
+	a.NotError(err).NotNil(s)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	s, err := schedulers.Parse("every:1m30s", time.UTC)
+	a.NotError(err).NotNil(s).
+		Equal(s.String(), "every:1m30s")
+	now := time.Now()
+	a.Equal(s.Next(now), now.Add(90*time.Second))
+
+	s, err = schedulers.Parse("every:-1s", time.UTC)
+	a.Error(err).Nil(s)
+	s, err = schedulers.Parse("every:1x", time.UTC)
+	a.Error(err).Nil(s)
+}
@@ -6,18 +6,32 @@ package scheduled
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 )
 
 // Server 管理所有的定时任务
+//
+// 所有的方法都是并发安全的，可以在 [Server.Serve] 运行期间添加或是取消任务。
 type Server struct {
-	jobs     []*Job
-	works    chan *Job     // 等待运行的任务
 	schedule chan struct{} // 重新执行调度任务
+	quit     chan struct{} // 调用 Shutdown 之后关闭，停止调度。
+	idle     chan struct{} // 调用 Shutdown 之后，所有任务都执行完毕时关闭。
+	idleOnce sync.Once
 
 	loc        *time.Location
-	running    bool
 	erro, info Logger
+
+	// 以下内容需要上锁
+
+	locker  sync.RWMutex
+	jobs    []*Job
+	running bool
+	gen     int // Serve 的运行次数
+	closed  bool
+	runs    map[*Job]int       // 正在执行的任务及其执行的次数
+	cancel  context.CancelFunc // 取消所有正在执行的任务
 }
 
 // NewServer 声明 [Server]
@@ -40,8 +54,10 @@ func NewServer(loc *time.Location, erro, info Logger) *Server {
 
 	return &Server{
 		jobs:     make([]*Job, 0, 100),
-		works:    make(chan *Job, 100),
 		schedule: make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		idle:     make(chan struct{}),
+		runs:     make(map[*Job]int, 10),
 
 		loc:  loc,
 		erro: erro,
@@ -53,39 +69,70 @@ func NewServer(loc *time.Location, erro, info Logger) *Server {
 func (s *Server) Location() *time.Location { return s.loc }
 
 // Serve 运行服务
+//
+// ctx 被取消时会立即返回，同时也会取消正在执行的任务；
+// 如果需要等待正在执行的任务完成，可以使用 [Server.Shutdown]，
+// 此时返回 [ErrServerClosed]。
 func (s *Server) Serve(ctx context.Context) error {
+	s.locker.Lock()
+	if s.closed {
+		s.locker.Unlock()
+		return ErrServerClosed
+	}
 	s.running = true
+	s.gen++
+	gen := s.gen
+	jobs := slices.Clone(s.jobs)
+	runCtx, cancel := context.WithCancel(ctx) // 传递给任务的 ctx，由 Shutdown 在超时时取消。
+	s.cancel = cancel
+	s.locker.Unlock()
+
+	// 初始化任务，之后添加的任务由 Server.Add 负责初始化。
+	// init 会调用用户的代码，所以在副本上进行，不能持有锁。
+	now := time.Now()
+	for _, job := range jobs {
+		job.init(now, gen)
+	}
+
 	defer func() {
+		s.locker.Lock()
 		s.running = false
+		s.locker.Unlock()
 	}()
 
-	// 初始化任务
-	now := time.Now()
-	for _, job := range s.jobs {
-		job.init(now)
-	}
-
 LOOP:
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case j := <-s.works:
-			go j.run(now, s.erro, s.info)
-		default: // 没有在执行的任务了，则计算一次时间
-			if len(s.jobs) == 0 {
-				<-time.After(time.Second) // 没有任务，1 秒后再次执行调试
-				continue LOOP
+		case <-s.quit:
+			return ErrServerClosed
+		default: // 计算一次时间
+			// 在副本上进行排序和遍历，期间可以随时添加或是取消任务。
+			jobs := s.snapshot()
+			sortJobs(jobs)
+
+			// 第一个需要等待执行的任务
+			i := slices.IndexFunc(jobs, (*Job).waiting)
+			if i < 0 { // 没有需要执行的任务，等待新任务的加入或是正在执行的任务完成。
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-s.quit:
+					return ErrServerClosed
+				case <-s.schedule:
+					continue LOOP
+				}
 			}
 
 			now = time.Now()
-
-			sortJobs(s.jobs)
-			dur := s.jobs[0].Next().Sub(now)
-			if dur > 0 { //
+			dur := jobs[i].Next().Sub(now)
+			if dur > 0 {
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
+				case <-s.quit:
+					return ErrServerClosed
 				case <-time.After(dur):
 					continue LOOP
 				case <-s.schedule:
@@ -93,13 +140,108 @@ LOOP:
 				}
 			}
 
-			for _, j := range s.jobs {
-				if !j.Next().IsZero() && ((j.Next().Before(now) || j.Next().Equal(now)) &&
-					(!j.Delay() || j.State() != Running)) {
+			for _, j := range jobs[i:] {
+				if j.waiting() && !j.Next().After(now) {
+					if !s.start(j) {
+						return ErrServerClosed
+					}
 					j.calcState(now) // 先计算状态，再异步运行。
-					s.works <- j
+					go s.run(runCtx, j, now)
 				}
 			}
 		}
 	}
 }
+
+// 运行任务 j，任务的执行不会阻塞调度。
+func (s *Server) run(ctx context.Context, j *Job, at time.Time) {
+	j.run(ctx, at, s.erro, s.info)
+	s.finish(j)
+	s.reschedule() // 任务执行完之后 next 可能已经改变
+}
+
+// 记录任务 j 开始执行，如果已经调用了 Shutdown，返回 false。
+func (s *Server) start(j *Job) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.runs[j]++
+	return true
+}
+
+// 记录任务 j 执行完成
+func (s *Server) finish(j *Job) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.runs[j]--; s.runs[j] <= 0 {
+		delete(s.runs, j)
+	}
+	if s.closed && len(s.runs) == 0 {
+		s.idleOnce.Do(func() { close(s.idle) })
+	}
+}
+
+// Shutdown 停止调度并等待正在执行的任务完成
+//
+// 调用之后 [Server.Serve] 会返回 [ErrServerClosed]，且不会再执行新的任务。
+// 如果在 ctx 取消之前所有任务都执行完毕，返回 nil；
+// 否则会取消正在执行的任务的 ctx，并返回 [ShutdownError]，其中包含了依然在执行的任务。
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.locker.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.quit)
+	}
+	if len(s.runs) == 0 {
+		s.idleOnce.Do(func() { close(s.idle) })
+	}
+	s.locker.Unlock()
+
+	var jobs []*Job
+	select {
+	case <-s.idle:
+	case <-ctx.Done():
+		s.locker.Lock()
+		jobs = make([]*Job, 0, len(s.runs))
+		for j := range s.runs {
+			jobs = append(jobs, j)
+		}
+		s.locker.Unlock()
+	}
+
+	s.locker.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.locker.Unlock()
+
+	if len(jobs) == 0 {
+		return nil
+	}
+	sortJobs(jobs)
+	return &ShutdownError{Jobs: jobs, Err: ctx.Err()}
+}
+
+func (s *Server) snapshot() []*Job {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	return slices.Clone(s.jobs)
+}
+
+func (s *Server) isRunning() bool {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	return s.running
+}
+
+// 触发一次调度任务
+func (s *Server) reschedule() {
+	select {
+	case s.schedule <- struct{}{}:
+	default: // 已经有等待中的调度请求
+	}
+}
@@ -5,10 +5,13 @@
 package scheduled
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,30 +19,52 @@ import (
 	"github.com/issue9/localeutil"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
+
+	"github.com/issue9/scheduled/schedulers/at"
+	"github.com/issue9/scheduled/schedulers/ticker"
 )
 
+// 记录任务的执行时间，可以在任务中并发调用。
+type recorder struct {
+	locker sync.Mutex
+	list   []time.Time
+}
+
+func (r *recorder) record(t time.Time) error {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	r.list = append(r.list, t)
+	return nil
+}
+
+func (r *recorder) times() []time.Time {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	return slices.Clone(r.list)
+}
+
 func TestServer_Serve(t *testing.T) {
 	a := assert.New(t, false)
 	term := NewTermLogger(message.NewPrinter(language.SimplifiedChinese))
 	srv := NewServer(nil, term, term)
 	a.NotNil(srv)
 
-	tickers1 := make([]time.Time, 0, 20)
-	tickers2 := make([]time.Time, 0, 20)
-	tickers3 := make([]time.Time, 0, 20)
+	rec1 := &recorder{}
+	rec2 := &recorder{}
+	rec3 := &recorder{}
 
 	srv.Tick(localeutil.StringPhrase("ticker1-delay"), func(t time.Time) error {
-		tickers1 = append(tickers1, t)
+		rec1.record(t)
 		return nil
 	}, time.Second, false, true)
 
 	srv.Tick(localeutil.StringPhrase("ticker2-delay"), func(t time.Time) error {
-		tickers2 = append(tickers2, t)
+		rec2.record(t)
 		return nil
 	}, 2*time.Second, false, true)
 
 	srv.Tick(localeutil.StringPhrase("ticker3-imm-delay"), func(t time.Time) error {
-		tickers3 = append(tickers3, t)
+		rec3.record(t)
 		return nil
 	}, 2*time.Second, true, true)
 
@@ -49,6 +74,9 @@ func TestServer_Serve(t *testing.T) {
 	}()
 	time.Sleep(5 * time.Second)
 	cancel()
+	tickers1 := rec1.times()
+	tickers2 := rec2.times()
+	tickers3 := rec3.times()
 
 	a.NotEmpty(tickers1)
 	for i := 1; i < len(tickers1); i++ {
@@ -84,18 +112,19 @@ func TestServer_Serve_empty(t *testing.T) {
 		srv.Serve(ctx)
 	}()
 	time.Sleep(500 * time.Millisecond) // 等待 srv.Serve
-	a.True(srv.running)
+	a.True(srv.isRunning())
 
-	tickers1 := make([]time.Time, 0, 20)
+	rec1 := &recorder{}
 
 	srv.Tick(localeutil.StringPhrase("empty-ticker1"), func(t time.Time) error {
-		tickers1 = append(tickers1, t)
+		rec1.record(t)
 		println("empty-ticker1", t.String())
 		return nil
 	}, time.Second, true, false)
 
 	time.Sleep(5 * time.Second)
 	cancel()
+	tickers1 := rec1.times()
 
 	a.NotEmpty(tickers1)
 	for i := 1; i < len(tickers1); i++ {
@@ -116,9 +145,9 @@ func TestServer_Serve_zero(t *testing.T) {
 	a.NotNil(srv)
 
 	// zero 应该永远不会被执行。
-	tickers1 := make([]time.Time, 0, 20)
+	rec1 := &recorder{}
 	srv.New(localeutil.StringPhrase("zero-ticker1"), func(t time.Time) error {
-		tickers1 = append(tickers1, t)
+		rec1.record(t)
 		println("zero-ticker1", t.String())
 		return nil
 	}, zero{}, false)
@@ -129,17 +158,19 @@ func TestServer_Serve_zero(t *testing.T) {
 		srv.Serve(ctx)
 	}()
 	time.Sleep(500 * time.Millisecond) // 等待 srv.Serve
-	a.True(srv.running)
+	a.True(srv.isRunning())
 
-	tickers2 := make([]time.Time, 0, 20)
+	rec2 := &recorder{}
 	srv.Tick(localeutil.StringPhrase("zero-ticker2"), func(t time.Time) error {
-		tickers2 = append(tickers2, t)
+		rec2.record(t)
 		println("zero-ticker2", t.String())
 		return nil
 	}, time.Second, true, false)
 
 	time.Sleep(5 * time.Second)
 	cancel()
+	tickers1 := rec1.times()
+	tickers2 := rec2.times()
 
 	a.Empty(tickers1)
 	a.NotEmpty(tickers2)
@@ -156,17 +187,17 @@ func TestServer_Serve_delay(t *testing.T) {
 	srv := NewServer(nil, nil, nil)
 	a.NotNil(srv)
 
-	tickers1 := make([]time.Time, 0, 20)
+	rec1 := &recorder{}
 	srv.Tick(localeutil.StringPhrase("delay-ticker1"), func(t time.Time) error {
-		tickers1 = append(tickers1, t)
+		rec1.record(t)
 		println("delay-ticker1", t.String())
 		time.Sleep(2 * time.Second)
 		return nil
 	}, time.Second, true, true)
 
-	tickers2 := make([]time.Time, 0, 20)
+	rec2 := &recorder{}
 	srv.Tick(localeutil.StringPhrase("delay-ticker2"), func(t time.Time) error {
-		tickers2 = append(tickers2, t)
+		rec2.record(t)
 		println("delay-ticker2", t.String())
 		time.Sleep(2 * time.Second)
 		return nil
@@ -177,18 +208,20 @@ func TestServer_Serve_delay(t *testing.T) {
 		srv.Serve(ctx)
 	}()
 	time.Sleep(500 * time.Millisecond) // 等待 srv.Serve
-	a.True(srv.running)
+	a.True(srv.isRunning())
 
 	time.Sleep(5 * time.Second)
 	cancel()
+	tickers1 := rec1.times()
+	tickers2 := rec2.times()
 
 	a.NotEmpty(tickers1).
 		NotEmpty(tickers2)
 	for i := 1; i < len(tickers1); i++ {
 		prev := tickers1[i-1].Unix()
 		curr := tickers1[i].Unix()
-		delta := math.Abs(float64(curr - prev)) // 缺失一次执行，应该介于 4-6 之间？
-		a.True(delta >= 4 && delta < 6, "v1=%d, v2=%d", prev, curr)
+		delta := math.Abs(float64(curr - prev)) // 执行完成之后再等待 1 秒，应该介于 3-4 之间。
+		a.True(delta >= 3 && delta <= 4, "v1=%d, v2=%d", prev, curr)
 	}
 	for i := 1; i < len(tickers2); i++ {
 		prev := tickers2[i-1].Unix()
@@ -198,6 +231,167 @@ func TestServer_Serve_delay(t *testing.T) {
 	}
 }
 
+// 长时间运行的任务不应该影响其它任务的调度
+func TestServer_Serve_block(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	exit := make(chan struct{})
+	var blocks atomic.Int64
+	srv.Tick(localeutil.StringPhrase("block-ticker1"), func(time.Time) error {
+		blocks.Add(1)
+		<-exit
+		return nil
+	}, 10*time.Millisecond, true, true)
+
+	rec := &recorder{}
+	srv.Tick(localeutil.StringPhrase("block-ticker2"), rec.record, 100*time.Millisecond, true, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		srv.Serve(ctx)
+	}()
+	time.Sleep(1050 * time.Millisecond)
+
+	a.Equal(blocks.Load(), 1) // delay 任务未执行完，不会再次执行。
+	a.True(len(rec.times()) >= 9, len(rec.times()))
+	for _, j := range srv.Jobs() {
+		if j.Title().LocaleString(nil) == "block-ticker1" {
+			a.Equal(j.State(), Running)
+		}
+	}
+
+	close(exit) // 执行完之后继续调度
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	a.True(blocks.Load() > 1)
+}
+
+func TestServer_Serve_context(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	a.PanicString(func() {
+		srv.NewContext(localeutil.StringPhrase("invalid"), nil, ticker.Tick(time.Second, false), false, -1)
+	}, "invalid duration -1ns")
+
+	errs := make(chan error, 2)
+	wait := func(ctx context.Context, _ time.Time) error {
+		<-ctx.Done()
+		errs <- ctx.Err()
+		return ctx.Err()
+	}
+	cancelJob := srv.NewContext(localeutil.StringPhrase("context-cancel"), wait, ticker.Tick(time.Second, true), false, 0)
+	srv.NewContext(localeutil.StringPhrase("context-serve"), wait, ticker.Tick(time.Second, true), false, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exit := make(chan struct{})
+	go func() {
+		srv.Serve(ctx)
+		close(exit)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	cancelJob() // 取消任务时，正在执行的任务也会收到通知。
+	select {
+	case err := <-errs:
+		a.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		a.TB().Fatal("任务未被取消")
+	}
+	a.Length(srv.Jobs(), 1)
+
+	cancel() // 取消 Serve 时，正在执行的任务也会收到通知。
+	<-exit
+	select {
+	case err := <-errs:
+		a.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		a.TB().Fatal("任务未被取消")
+	}
+}
+
+// Scheduler.Next 中调用 Server 的方法不会死锁
+func TestServer_reentrant(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	var once sync.Once
+	s := SchedulerFunc(func(last time.Time) time.Time {
+		srv.Jobs()
+		once.Do(func() { // 在 Next 中注册任务
+			srv.New(localeutil.StringPhrase("reentrant-inner"), func(time.Time) error { return nil }, ticker.Tick(time.Hour, false), false)
+		})
+		return last.Add(time.Hour)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.New(localeutil.StringPhrase("reentrant-before"), func(time.Time) error { return nil }, s, false)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			srv.Serve(ctx)
+		}()
+		time.Sleep(100 * time.Millisecond) // 等待 srv.Serve
+
+		srv.New(localeutil.StringPhrase("reentrant-after"), func(time.Time) error { return nil }, s, false)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		a.TB().Fatal("死锁")
+	}
+	a.Length(srv.Jobs(), 3)
+}
+
+// 运行过程中修改 at.Set 中的时间点
+func TestServer_Serve_set(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	now := time.Now()
+	set := at.NewSet(now.Add(300 * time.Millisecond))
+	rec := &recorder{}
+	cancel := srv.New(localeutil.StringPhrase("set"), rec.record, set, false)
+
+	ctx, cancelServe := context.WithCancel(context.Background())
+	defer cancelServe()
+	go func() {
+		srv.Serve(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond) // 等待 srv.Serve
+
+	// 删除下一次执行的时间点
+	set.Remove(set.Times()...)
+	time.Sleep(300 * time.Millisecond)
+	a.Empty(rec.times())
+
+	// 时间点已经耗尽之后再添加
+	set.Add(time.Now().Add(100 * time.Millisecond))
+	time.Sleep(200 * time.Millisecond)
+	a.Length(rec.times(), 1)
+
+	// 添加比下一次执行更早的时间点
+	set.Add(time.Now().Add(time.Hour))
+	set.Add(time.Now().Add(100 * time.Millisecond))
+	time.Sleep(200 * time.Millisecond)
+	a.Length(rec.times(), 2)
+
+	// 取消之后不再响应
+	cancel()
+	set.Add(time.Now().Add(50 * time.Millisecond))
+	time.Sleep(150 * time.Millisecond)
+	a.Length(rec.times(), 2)
+}
+
 func TestServer_Serve_loc(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -206,14 +400,8 @@ func TestServer_Serve_loc(t *testing.T) {
 	srv := NewServer(loc, errlog, nil)
 	a.NotNil(srv)
 
-	buf := new(bytes.Buffer)
-	a.Equal(0, buf.Len())
-	job := func(t time.Time) error {
-		buf.WriteString("job run at ")
-		buf.WriteString(t.String())
-		buf.WriteString("\n")
-		return nil
-	}
+	rec := &recorder{}
+	job := func(t time.Time) error { return rec.record(t) }
 
 	now := time.Now().Add(2 * time.Second)
 	_, m, d := now.Date()
@@ -227,5 +415,212 @@ func TestServer_Serve_loc(t *testing.T) {
 	}()
 	time.Sleep(4 * time.Second) // 等待 4 秒
 	cancel()
-	a.Equal(0, buf.Len(), buf.String())
+	a.Empty(rec.times())
+}
+
+// 小于 1 秒的任务以及对齐时钟的任务
+func TestServer_Serve_subsecond(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	var locker sync.Mutex
+	tickers1 := make([]time.Time, 0, 20)
+	srv.Tick(localeutil.StringPhrase("subsecond-ticker1"), func(t time.Time) error {
+		locker.Lock()
+		defer locker.Unlock()
+		tickers1 = append(tickers1, t)
+		return nil
+	}, 250*time.Millisecond, false, false)
+
+	tickers2 := make([]time.Time, 0, 20)
+	srv.TickAligned(localeutil.StringPhrase("subsecond-aligned"), func(t time.Time) error {
+		locker.Lock()
+		defer locker.Unlock()
+		tickers2 = append(tickers2, t)
+		return nil
+	}, 500*time.Millisecond, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		srv.Serve(ctx)
+	}()
+	time.Sleep(2100 * time.Millisecond)
+	cancel()
+
+	locker.Lock()
+	defer locker.Unlock()
+
+	a.True(len(tickers1) >= 7, "%d", len(tickers1))
+	for i := 1; i < len(tickers1); i++ {
+		a.Equal(tickers1[i].Sub(tickers1[i-1]).Round(50*time.Millisecond), 250*time.Millisecond)
+	}
+
+	a.True(len(tickers2) >= 3, "%d", len(tickers2))
+	for _, t := range tickers2 { // 传递给任务的是实际的执行时间，会有少许的延后。
+		a.Equal(t.Round(50*time.Millisecond).Nanosecond()%int(500*time.Millisecond), 0, t)
+	}
+}
+
+// 在 Serve 运行期间并发地添加和取消大量的任务，需要配合 -race 使用。
+func TestServer_concurrent(t *testing.T) {
+	a := assert.New(t, false)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	var count atomic.Int64
+	f := func(time.Time) error {
+		count.Add(1)
+		return nil
+	}
+
+	const workers, jobs = 8, 500
+
+	var wg sync.WaitGroup
+	var locker sync.Mutex
+	remains := make([]context.CancelFunc, 0, workers*jobs/2)
+
+	add := func(w int) { // 添加任务，并取消其中的一半。
+		defer wg.Done()
+		for i := range jobs {
+			title := localeutil.StringPhrase(fmt.Sprintf("concurrent-%d-%d", w, i))
+			cancel := srv.Tick(title, f, 5*time.Millisecond, true, i%3 == 0)
+			if i%2 == 0 {
+				cancel()
+			} else {
+				locker.Lock()
+				remains = append(remains, cancel)
+				locker.Unlock()
+			}
+			if i%50 == 1 { // 刚添加的任务未被取消
+				a.NotEmpty(srv.Jobs())
+			}
+		}
+	}
+
+	// Serve 启动前后都有任务加入
+	wg.Add(workers)
+	go add(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	exit := make(chan struct{})
+	go func() {
+		a.ErrorIs(srv.Serve(ctx), context.Canceled)
+		close(exit)
+	}()
+
+	for w := 1; w < workers; w++ {
+		go add(w)
+	}
+	wg.Wait()
+	a.Length(srv.Jobs(), workers*jobs/2)
+
+	time.Sleep(50 * time.Millisecond)
+	a.True(count.Load() > 0)
+
+	// 并发取消剩余的任务
+	wg.Add(workers)
+	for w := range workers {
+		go func() {
+			defer wg.Done()
+			for i := w; i < len(remains); i += workers {
+				remains[i]()
+			}
+		}()
+	}
+	wg.Wait()
+	a.Empty(srv.Jobs())
+
+	// 所有任务已经取消，不会再被执行。
+	time.Sleep(100 * time.Millisecond) // 等待已经开始的任务执行完毕
+	c := count.Load()
+	time.Sleep(100 * time.Millisecond)
+	a.Equal(count.Load(), c)
+
+	// 任务全部取消之后，依然可以添加任务。
+	rec := &recorder{}
+	srv.Tick(localeutil.StringPhrase("concurrent-last"), rec.record, 10*time.Millisecond, true, false)
+	time.Sleep(100 * time.Millisecond)
+	a.NotEmpty(rec.times())
+
+	cancel()
+	<-exit
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 在 Serve 之前调用
+	srv := NewServer(nil, nil, nil)
+	a.NotError(srv.Shutdown(context.Background())).
+		ErrorIs(srv.Serve(context.Background()), ErrServerClosed)
+
+	// 没有正在执行的任务，Serve 在等待下一次执行时间。
+	srv = NewServer(nil, nil, nil)
+	srv.Tick(localeutil.StringPhrase("shutdown-idle"), func(time.Time) error { return nil }, time.Hour, false, false)
+	exit := make(chan error, 1)
+	go func() { exit <- srv.Serve(context.Background()) }()
+	time.Sleep(50 * time.Millisecond) // 等待 Serve 进入等待状态
+	a.NotError(srv.Shutdown(context.Background()))
+	select {
+	case err := <-exit:
+		a.ErrorIs(err, ErrServerClosed)
+	case <-time.After(time.Second):
+		a.TB().Fatal("Serve 未返回")
+	}
+
+	// 等待任务完成
+	srv = NewServer(nil, nil, nil)
+	start := make(chan struct{}, 100)
+	var finished atomic.Int64
+	srv.Tick(localeutil.StringPhrase("shutdown-wait"), func(time.Time) error {
+		start <- struct{}{}
+		time.Sleep(300 * time.Millisecond)
+		finished.Add(1)
+		return nil
+	}, 10*time.Millisecond, true, false)
+
+	go func() { exit <- srv.Serve(context.Background()) }()
+	<-start
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	a.NotError(srv.Shutdown(ctx)).
+		ErrorIs(<-exit, ErrServerClosed)
+	runs := len(start) + 1
+	a.Equal(finished.Load(), runs) // 所有已经开始的任务都已经完成
+	time.Sleep(50 * time.Millisecond)
+	a.Equal(finished.Load(), runs) // 不会再有新的任务
+	a.NotError(srv.Shutdown(context.Background()))
+
+	// 超时
+	srv = NewServer(nil, nil, nil)
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	srv.NewContext(localeutil.StringPhrase("shutdown-timeout"), func(ctx context.Context, _ time.Time) error {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	}, ticker.Tick(time.Hour, true), false, 0)
+	go func() { exit <- srv.Serve(context.Background()) }()
+	<-started
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	a.ErrorIs(err, context.DeadlineExceeded).
+		ErrorIs(<-exit, ErrServerClosed)
+	var se *ShutdownError
+	a.True(errors.As(err, &se)).
+		Length(se.Jobs, 1).
+		Equal(se.Jobs[0].Title().LocaleString(nil), "shutdown-timeout").
+		Equal(se.Error(), "jobs still running after shutdown: shutdown-timeout")
+
+	select { // 超时之后会取消正在执行的任务
+	case <-canceled:
+	case <-time.After(time.Second):
+		a.TB().Fatal("任务未被取消")
+	}
 }